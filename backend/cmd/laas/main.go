@@ -8,14 +8,13 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
-	"github.com/ChayanDass/beneficiary-manager/pkg/api"
 	"github.com/ChayanDass/beneficiary-manager/pkg/db"
-	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/migrations"
 	"github.com/joho/godotenv"
-	"gorm.io/gorm/clause"
 )
 
 // declare flags to input the basic requirement of database connection and the path of the data file
@@ -34,6 +33,20 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+const usage = `usage: laas [flags] [command] [arguments]
+
+commands:
+  serve          run the HTTP server (default)
+  migrate        apply or revert database migrations
+  seed           insert default document types and an optional admin account
+  user           create users, change roles and reset passwords
+  scheme         import and export schemes
+  application    export applications
+
+Run "laas <command>" without arguments for help on a command.
+
+flags:`
+
 func main() {
 	err := godotenv.Load(".env")
 
@@ -41,52 +54,76 @@ func main() {
 		log.Fatalf("Error loading .env file")
 	}
 
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
-	db.Connect(dbhost, port, user, dbname, password)
-	r := api.Router()
 
-	if err := db.DB.AutoMigrate(&models.Application{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
+	command, args := flag.Arg(0), []string{}
+	if flag.NArg() > 1 {
+		args = flag.Args()[1:]
 	}
-	if err := db.DB.AutoMigrate(&models.User{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
 
+	var run func(args []string)
+	switch command {
+	case "", "serve":
+		run = runServe
+	case "migrate":
+		run = runMigrate
+	case "seed":
+		run = runSeed
+	case "user":
+		run = runUser
+	case "scheme":
+		run = runScheme
+	case "application":
+		run = runApplication
+	case "help":
+		flag.Usage()
+		return
+	default:
+		flag.Usage()
+		os.Exit(2)
 	}
-	if err := db.DB.AutoMigrate(&models.StudentAcademicQualification{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
 
-	}
-	if err := db.DB.AutoMigrate(&models.Scheme{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
-	}
-	if err := db.DB.AutoMigrate(&models.Eligibility{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
-	}
-	if err := db.DB.AutoMigrate(&models.Address{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
-	}
-	if err := db.DB.AutoMigrate(&models.StudentProfile{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
+	run(args)
+}
 
-	}
+// connectDB connects to the database given by the global flags.
+// Commands call it after validating their arguments so usage errors need no database.
+// It is a variable so tests can point the commands at a throwaway schema.
+var connectDB = func() {
+	db.Connect(dbhost, port, user, dbname, password)
+}
 
-	if err := db.DB.AutoMigrate(&models.UploadDocument{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
+// requireCurrentSchema connects to the database and stops the command unless all
+// migrations have been applied.
+func requireCurrentSchema() {
+	connectDB()
+	if err := migrations.Check(db.DB); err != nil {
+		log.Fatalf("Refusing to start: %v. Run `laas migrate up` first.", err)
 	}
+}
 
-	if err := db.DB.AutoMigrate(&models.DocumentsRequired{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
-	}
+// commandUsage prints the usage of a subcommand and exits.
+func commandUsage(text string) {
+	fmt.Fprintln(os.Stderr, text)
+	os.Exit(2)
+}
 
-	if err := db.DB.AutoMigrate(&models.EligibilityDocumentMap{}); err != nil {
-		log.Fatalf("Failed to automigrate database: %v", err)
+// outputFile opens the named file for writing, or stdout for an empty name or "-".
+func outputFile(name string) (*os.File, func()) {
+	if name == "" || name == "-" {
+		return os.Stdout, func() {}
 	}
-	if err := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DefaultDocumentsRequired).Error; err != nil {
-		log.Fatalf("Failed to seed database with default documents types: %s", err.Error())
+	f, err := os.Create(name)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", name, err)
 	}
-
-	if err := r.Run(); err != nil {
-		log.Fatalf("Error while running the server: %v", err)
+	return f, func() {
+		if err := f.Close(); err != nil {
+			log.Fatalf("Failed to write %s: %v", name, err)
+		}
 	}
-
 }
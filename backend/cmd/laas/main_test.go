@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+)
+
+// commandEnv holds the JSON encoded arguments of a command the test binary runs in place of the
+// tests; see runCommand.
+const commandEnv = "LAAS_TEST_COMMAND"
+
+// connectedMarker is printed by a command run through runCommand if it reaches the database.
+const connectedMarker = "connecting to the database"
+
+var commands = map[string]func(args []string){
+	"migrate":     runMigrate,
+	"seed":        runSeed,
+	"user":        runUser,
+	"scheme":      runScheme,
+	"application": runApplication,
+}
+
+func TestMain(m *testing.M) {
+	if encoded := os.Getenv(commandEnv); encoded != "" {
+		var args []string
+		if err := json.Unmarshal([]byte(encoded), &args); err != nil {
+			panic(err)
+		}
+		connectDB = func() {
+			os.Stderr.WriteString(connectedMarker)
+			os.Exit(3)
+		}
+		commands[args[0]](args[1:])
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCommand runs a command in a child process, as failing commands exit, and returns its exit
+// code and output.
+func runCommand(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), commandEnv+"="+string(encoded))
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), string(out)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 0, string(out)
+}
+
+func TestCommandErrorsNeedNoDatabase(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{"migrate"}, 2, "usage: laas migrate"},
+		{[]string{"seed", "extra"}, 2, "usage: laas seed"},
+		{[]string{"user"}, 2, "usage: laas user"},
+		{[]string{"user", "create", "-username", "asha"}, 2, "usage: laas user"},
+		{[]string{"user", "set-role", "asha"}, 2, "usage: laas user"},
+		{[]string{"user", "set-role", "asha", "superuser"}, 1, `Unknown role "superuser"`},
+		{[]string{"user", "reset-password"}, 2, "usage: laas user"},
+		{[]string{"scheme", "publish"}, 2, "usage: laas scheme"},
+		{[]string{"scheme", "import"}, 2, "usage: laas scheme"},
+		{[]string{"scheme", "update-statuses", "now"}, 2, "usage: laas scheme"},
+		{[]string{"scheme", "export", "-format", "xml"}, 1, "unsupported"},
+		{[]string{"scheme", "import", filepath.Join(t.TempDir(), "missing.json")}, 1, "Failed to open"},
+		{[]string{"application"}, 2, "usage: laas application"},
+		{[]string{"application", "export", "-format", "xml"}, 2, "usage: laas application"},
+		{[]string{"application", "export", "-status", "pending"}, 1, `Unknown status "pending"`},
+	}
+	for _, tt := range tests {
+		name := strings.Join(tt.args, " ")
+		code, out := runCommand(t, tt.args...)
+		if code != tt.code || !strings.Contains(out, tt.output) {
+			t.Errorf("laas %s exited with %d and %q, want %d and %q", name, code, out, tt.code, tt.output)
+		}
+		if strings.Contains(out, connectedMarker) {
+			t.Errorf("laas %s connected to the database before failing", name)
+		}
+	}
+}
+
+// useCommandDB points the commands at a migrated throwaway schema.
+func useCommandDB(t *testing.T) {
+	t.Helper()
+	conn := dbtest.Migrated(t)
+	previous, previousConnect := db.DB, connectDB
+	connectDB = func() { db.DB = conn }
+	db.DB = conn
+	t.Cleanup(func() { db.DB, connectDB = previous, previousConnect })
+}
+
+// captureStdout runs a command in this process and returns what it printed.
+func captureStdout(t *testing.T, run func(args []string), args ...string) string {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	previous := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = previous }()
+
+	run(args)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestUserCommands(t *testing.T) {
+	useCommandDB(t)
+
+	out := captureStdout(t, runUser, "create", "-username", "asha", "-email", "asha@example.org", "-role", "reviewer")
+	match := regexp.MustCompile(`generated password: (\S+)`).FindStringSubmatch(out)
+	if !strings.Contains(out, "user asha created") || match == nil {
+		t.Fatalf("user create printed %q, want the account and its generated password", out)
+	}
+	user, err := utils.AuthenticateUser(db.DB, "asha", match[1])
+	if err != nil {
+		t.Fatalf("the generated password does not work: %v", err)
+	}
+	if user.Role != models.RoleReviewer {
+		t.Errorf("created role = %s, want reviewer", user.Role)
+	}
+
+	if out := captureStdout(t, runUser, "set-role", "asha", "admin"); !strings.Contains(out, "user asha is now admin") {
+		t.Errorf("set-role printed %q", out)
+	}
+	if err := db.DB.First(user, user.ID).Error; err != nil || user.Role != models.RoleAdmin {
+		t.Errorf("role = %s (%v), want admin", user.Role, err)
+	}
+
+	if out := captureStdout(t, runUser, "reset-password", "-password", "correct horse battery", "asha"); !strings.Contains(out, "password of asha updated") {
+		t.Errorf("reset-password printed %q", out)
+	}
+	if _, err := utils.AuthenticateUser(db.DB, "asha", "correct horse battery"); err != nil {
+		t.Errorf("the new password does not work: %v", err)
+	}
+	if out := captureStdout(t, runUser, "reset-password", "asha"); !strings.Contains(out, "reset token for asha") {
+		t.Errorf("reset-password without a password printed %q, want a reset token", out)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	useCommandDB(t)
+
+	out := captureStdout(t, runSeed, "-admin-username", "root", "-admin-email", "root@example.org", "-admin-password", "correct horse battery")
+	if !strings.Contains(out, "admin account root created") {
+		t.Errorf("seed printed %q, want the admin account", out)
+	}
+	if _, err := utils.AuthenticateUser(db.DB, "root", "correct horse battery"); err != nil {
+		t.Errorf("the admin account does not work: %v", err)
+	}
+	if out := captureStdout(t, runSeed); !strings.Contains(out, "document types: 0 added") {
+		t.Errorf("repeated seed printed %q, want no new document types", out)
+	}
+}
+
+func TestSchemeCommands(t *testing.T) {
+	useCommandDB(t)
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	inputs := []models.SchemeInput{
+		{Name: "Merit scholarship", Amount: 5000, StartDate: now.AddDate(0, -1, 0), EndDate: now.AddDate(1, 0, 0), Status: models.SchemeStatusOpen,
+			Eligibility: models.EligibilityInput{Category: models.CategoryGeneral, Documents: []models.EligibilityDocumentInput{{Name: "aadhar_card", IsMandatory: true}}}},
+		{Name: "Sports scholarship", Amount: 3000, StartDate: now.AddDate(0, 1, 0), EndDate: now.AddDate(1, 0, 0), Status: models.SchemeStatusUpcoming,
+			Eligibility: models.EligibilityInput{Category: models.CategoryGeneral}},
+	}
+	raw, err := json.Marshal(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(dir, "schemes.json")
+	if err := os.WriteFile(source, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	countSchemes := func() int64 {
+		var n int64
+		if err := db.DB.Model(&models.Scheme{}).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if out := captureStdout(t, runScheme, "import", "-dry-run", source); !strings.Contains(out, "dry run: 2 created, 0 updated, 0 failed") || countSchemes() != 0 {
+		t.Errorf("dry run printed %q and left %d schemes, want nothing saved", out, countSchemes())
+	}
+	if out := captureStdout(t, runScheme, "import", source); !strings.Contains(out, "imported: 2 created, 0 updated, 0 failed") || countSchemes() != 2 {
+		t.Errorf("import printed %q and left %d schemes, want 2 created", out, countSchemes())
+	}
+
+	exported := filepath.Join(dir, "export.csv")
+	captureStdout(t, runScheme, "export", "-o", exported)
+	if out := captureStdout(t, runScheme, "import", exported); !strings.Contains(out, "imported: 0 created, 2 updated, 0 failed") {
+		t.Errorf("importing the CSV export printed %q, want both schemes updated", out)
+	}
+
+	var decoded []models.SchemeInput
+	if err := json.Unmarshal([]byte(captureStdout(t, runScheme, "export", "-format", "json")), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0].Name != "Merit scholarship" || len(decoded[0].Eligibility.Documents) != 1 {
+		t.Errorf("JSON export = %+v, want both schemes with their documents", decoded)
+	}
+
+	// The upcoming scheme starts once its start date has passed
+	if err := db.DB.Model(&models.Scheme{}).Where("name = ?", "Sports scholarship").Update("start_date", now.AddDate(0, 0, -1)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if out := captureStdout(t, runScheme, "update-statuses"); !strings.Contains(out, "1 opened, 0 closed") {
+		t.Errorf("update-statuses printed %q, want one scheme opened", out)
+	}
+}
+
+func TestApplicationExport(t *testing.T) {
+	useCommandDB(t)
+	now := time.Now()
+
+	applicant, err := utils.CreateUser(db.DB, "asha", "asha@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheme, err := utils.CreateScheme(db.DB, models.SchemeInput{
+		Name: "Merit scholarship", Amount: 5000, StartDate: now.AddDate(0, -1, 0), EndDate: now.AddDate(1, 0, 0), Status: models.SchemeStatusOpen,
+		Eligibility: models.EligibilityInput{Category: models.CategoryGeneral},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := models.StudentProfile{UserID: applicant.ID, FullName: "=HYPERLINK(\"https://evil.example.org\")", Email: "asha@example.org"}
+	if err := db.DB.Create(&profile).Error; err != nil {
+		t.Fatal(err)
+	}
+	application := models.Application{
+		UserID: applicant.ID, SchemeID: scheme.ID, StudentProfileID: profile.ID,
+		Status: models.ApplicationStatusSubmitted, SubmittedAt: &now,
+	}
+	if err := db.DB.Omit("User", "Scheme", "StudentProfile").Create(&application).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	readCSV := func(args ...string) [][]string {
+		t.Helper()
+		rows, err := csv.NewReader(strings.NewReader(captureStdout(t, runApplication, append([]string{"export"}, args...)...))).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	rows := readCSV()
+	if len(rows) != 2 || strings.Join(rows[0], ",") != strings.Join(applicationCSVHeader, ",") {
+		t.Fatalf("CSV export = %v, want the header and one application", rows)
+	}
+	if got := rows[1][7]; got != "'"+profile.FullName {
+		t.Errorf("full_name cell = %q, want the formula escaped", got)
+	}
+	if got := rows[1][5]; got != string(models.ApplicationStatusSubmitted) {
+		t.Errorf("status cell = %q, want submitted", got)
+	}
+	if rows := readCSV("-status", "approved"); len(rows) != 1 {
+		t.Errorf("export of approved applications has %d rows, want only the header", len(rows))
+	}
+
+	var exported []struct {
+		ID       uint `json:"id"`
+		SchemeID uint `json:"scheme_id"`
+	}
+	out := captureStdout(t, runApplication, "export", "-format", "json", "-scheme", strconv.FormatUint(uint64(scheme.ID), 10))
+	if err := json.Unmarshal([]byte(out), &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 1 || exported[0].ID != application.ID || exported[0].SchemeID != scheme.ID {
+		t.Errorf("JSON export = %+v, want application %d", exported, application.ID)
+	}
+}
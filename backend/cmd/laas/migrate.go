@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/migrations"
+)
+
+const migrateUsage = `usage: laas migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [n]        revert the last n migrations (default 1)
+  status          list migrations and when they were applied
+  to <version>    migrate up or down to a version (0 reverts everything)`
+
+// runMigrate executes a migrate subcommand against the connected database.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		commandUsage(migrateUsage)
+	}
+	connectDB()
+
+	var (
+		done []migrations.Migration
+		err  error
+	)
+	switch args[0] {
+	case "up":
+		done, err = migrations.Up(db.DB)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("Invalid number of migrations %q", args[1])
+			}
+		}
+		done, err = migrations.Down(db.DB, steps)
+	case "to":
+		if len(args) < 2 {
+			log.Fatalf("Missing target version")
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil || version < 0 {
+			log.Fatalf("Invalid version %q", args[1])
+		}
+		done, err = migrations.To(db.DB, version)
+	case "status":
+		printMigrationStatus()
+		return
+	default:
+		commandUsage(migrateUsage)
+	}
+
+	for _, m := range done {
+		fmt.Printf("%04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	current, err := migrations.Current(db.DB)
+	if err != nil {
+		log.Fatalf("Failed to read schema version: %v", err)
+	}
+	fmt.Printf("schema is at version %d\n", current)
+}
+
+func printMigrationStatus() {
+	statuses, err := migrations.StatusOf(db.DB)
+	if err != nil {
+		log.Fatalf("Failed to read migration status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+)
+
+const schemeUsage = `usage: laas scheme <command>
+
+commands:
+  export [-format json|yaml|csv] [-o file]
+                    write all schemes with eligibility and documents (stdout by default)
+  import [-format json|yaml|csv] [-dry-run] <file>
+                    create or update schemes by name ("-" reads stdin); nothing is
+                    saved if any row fails
+  update-statuses   open and close schemes whose start or end date has passed
+
+The format defaults to the file extension, or json.`
+
+// runScheme executes a scheme subcommand.
+func runScheme(args []string) {
+	if len(args) == 0 {
+		commandUsage(schemeUsage)
+	}
+
+	switch args[0] {
+	case "export":
+		exportSchemes(args[1:])
+	case "import":
+		importSchemes(args[1:])
+	case "update-statuses":
+		if len(args) > 1 {
+			commandUsage(schemeUsage)
+		}
+		requireCurrentSchema()
+		opened, closed, err := utils.AdvanceSchemeStatuses(db.DB, time.Now())
+		if err != nil {
+			log.Fatalf("Failed to update scheme statuses: %v", err)
+		}
+		fmt.Printf("%d opened, %d closed\n", opened, closed)
+	default:
+		commandUsage(schemeUsage)
+	}
+}
+
+// schemeFormat resolves the -format flag, falling back to the file extension and then to JSON.
+func schemeFormat(flagValue, name string) utils.SchemeFormat {
+	value := flagValue
+	if value == "" {
+		value = string(utils.SchemeFormatJSON)
+		if name != "" && name != "-" && filepath.Ext(name) != "" {
+			value = name
+		}
+	}
+	format, err := utils.ParseSchemeFormat(value)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return format
+}
+
+func exportSchemes(args []string) {
+	fs := flag.NewFlagSet("scheme export", flag.ExitOnError)
+	fs.Usage = func() { commandUsage(schemeUsage) }
+	formatFlag := fs.String("format", "", "json, yaml or csv")
+	output := fs.String("o", "", "output file")
+	fs.Parse(args)
+	format := schemeFormat(*formatFlag, *output)
+	requireCurrentSchema()
+
+	var schemes []models.Scheme
+	if err := db.DB.
+		Preload("Eligibility.DocumentMappings.Document").
+		Order("id ASC").
+		Find(&schemes).Error; err != nil {
+		log.Fatalf("Failed to fetch schemes: %v", err)
+	}
+
+	inputs := make([]models.SchemeInput, 0, len(schemes))
+	for i := range schemes {
+		inputs = append(inputs, utils.SchemeToInput(&schemes[i]))
+	}
+
+	out, done := outputFile(*output)
+	defer done()
+	if err := utils.EncodeSchemes(out, format, inputs); err != nil {
+		log.Fatalf("Failed to write schemes: %v", err)
+	}
+}
+
+func importSchemes(args []string) {
+	fs := flag.NewFlagSet("scheme import", flag.ExitOnError)
+	fs.Usage = func() { commandUsage(schemeUsage) }
+	formatFlag := fs.String("format", "", "json, yaml or csv")
+	dryRun := fs.Bool("dry-run", false, "validate without saving")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		commandUsage(schemeUsage)
+	}
+	name := fs.Arg(0)
+	format := schemeFormat(*formatFlag, name)
+
+	var in io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", name, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	records, err := utils.DecodeSchemes(in, format)
+	if err != nil {
+		log.Fatalf("Failed to parse schemes: %v", err)
+	}
+	requireCurrentSchema()
+
+	report, err := utils.ImportSchemes(db.DB, records, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed, nothing was saved: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tNAME\tACTION\tERROR")
+	for _, row := range report.Rows {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.Row, row.Name, row.Action, row.Error)
+	}
+	w.Flush()
+
+	summary := fmt.Sprintf("%d created, %d updated, %d failed", report.Created, report.Updated, report.Failed)
+	switch {
+	case report.Failed > 0:
+		log.Fatalf("Import failed, nothing was saved: %s", summary)
+	case *dryRun:
+		fmt.Printf("dry run: %s, nothing was saved\n", summary)
+	default:
+		fmt.Printf("imported: %s\n", summary)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"gorm.io/gorm/clause"
+)
+
+const seedUsage = `usage: laas seed [-admin-username name -admin-email email [-admin-password password]]
+
+Inserts the default document types if they are missing and, when -admin-username is
+given, creates an admin account. A random password is generated and printed when
+-admin-password is omitted.`
+
+// runSeed inserts default data.
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	fs.Usage = func() { commandUsage(seedUsage) }
+	adminUsername := fs.String("admin-username", "", "username of the admin account to create")
+	adminEmail := fs.String("admin-email", "", "email of the admin account")
+	adminPassword := fs.String("admin-password", "", "password of the admin account")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		commandUsage(seedUsage)
+	}
+	requireCurrentSchema()
+
+	result := db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DefaultDocumentsRequired)
+	if result.Error != nil {
+		log.Fatalf("Failed to seed default document types: %v", result.Error)
+	}
+	fmt.Printf("document types: %d added\n", result.RowsAffected)
+
+	if *adminUsername == "" {
+		return
+	}
+	pw, generated := passwordOrGenerated(*adminPassword)
+	admin, err := utils.CreateUser(db.DB, *adminUsername, *adminEmail, pw, models.RoleAdmin)
+	if err != nil {
+		log.Fatalf("Failed to create admin account: %v", err)
+	}
+	fmt.Printf("admin account %s created (id %d)\n", admin.Username, admin.ID)
+	if generated {
+		fmt.Printf("generated password: %s\n", pw)
+	}
+}
+
+// passwordOrGenerated returns the given password, or a random one if it is empty.
+func passwordOrGenerated(pw string) (string, bool) {
+	if pw != "" {
+		return pw, false
+	}
+	token, _, err := utils.GenerateToken()
+	if err != nil {
+		log.Fatalf("Failed to generate password: %v", err)
+	}
+	return token[:20], true
+}
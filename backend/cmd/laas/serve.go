@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/api"
+	"github.com/ChayanDass/beneficiary-manager/pkg/beckn"
+	"github.com/ChayanDass/beneficiary-manager/pkg/scheduler"
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+)
+
+// runServe runs the HTTP server.
+func runServe(args []string) {
+	if len(args) > 0 {
+		commandUsage("usage: laas serve")
+	}
+	if err := utils.CheckJWTSecret(); err != nil {
+		log.Fatalf("Refusing to start: %v. Generate one with `openssl rand -hex 32`.", err)
+	}
+	requireCurrentSchema()
+
+	storage.Setup()
+	beckn.Setup()
+	scheduler.Setup()
+	r := api.Router()
+
+	if err := r.Run(); err != nil {
+		log.Fatalf("Error while running the server: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const userUsage = `usage: laas user <command>
+
+commands:
+  create -username name -email email [-role role] [-password password]
+                    create an account; a random password is printed if none is given
+  set-role <username> <role>
+                    assign applicant, reviewer or admin
+  reset-password [-password password] <username>
+                    set a new password, or print a one-hour reset token if none is given`
+
+// runUser executes a user subcommand.
+func runUser(args []string) {
+	if len(args) == 0 {
+		commandUsage(userUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		createUser(args[1:])
+	case "set-role":
+		setUserRole(args[1:])
+	case "reset-password":
+		resetUserPassword(args[1:])
+	default:
+		commandUsage(userUsage)
+	}
+}
+
+func createUser(args []string) {
+	fs := flag.NewFlagSet("user create", flag.ExitOnError)
+	fs.Usage = func() { commandUsage(userUsage) }
+	username := fs.String("username", "", "username")
+	email := fs.String("email", "", "email address")
+	role := fs.String("role", string(models.RoleApplicant), "applicant, reviewer or admin")
+	pwFlag := fs.String("password", "", "password")
+	fs.Parse(args)
+	if *username == "" || *email == "" || fs.NArg() > 0 {
+		commandUsage(userUsage)
+	}
+	requireCurrentSchema()
+
+	pw, generated := passwordOrGenerated(*pwFlag)
+	created, err := utils.CreateUser(db.DB, *username, *email, pw, models.Role(*role))
+	if err != nil {
+		log.Fatalf("Failed to create user: %v", err)
+	}
+	fmt.Printf("user %s created (id %d, role %s)\n", created.Username, created.ID, created.Role)
+	if generated {
+		fmt.Printf("generated password: %s\n", pw)
+	}
+}
+
+func setUserRole(args []string) {
+	if len(args) != 2 {
+		commandUsage(userUsage)
+	}
+	role := models.Role(args[1])
+	if !role.IsValid() {
+		log.Fatalf("Unknown role %q", args[1])
+	}
+	requireCurrentSchema()
+
+	target := findUser(args[0])
+	if err := utils.SetRole(db.DB, target, role); err != nil {
+		log.Fatalf("Failed to update role: %v", err)
+	}
+	fmt.Printf("user %s is now %s\n", target.Username, role)
+}
+
+func resetUserPassword(args []string) {
+	fs := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+	fs.Usage = func() { commandUsage(userUsage) }
+	pw := fs.String("password", "", "new password")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		commandUsage(userUsage)
+	}
+	requireCurrentSchema()
+
+	target := findUser(fs.Arg(0))
+	if *pw != "" {
+		if err := utils.SetPassword(db.DB, target, *pw); err != nil {
+			log.Fatalf("Failed to set password: %v", err)
+		}
+		fmt.Printf("password of %s updated\n", target.Username)
+		return
+	}
+
+	reset, err := utils.IssuePasswordResetToken(db.DB, target.ID)
+	if err != nil {
+		log.Fatalf("Failed to issue password reset token: %v", err)
+	}
+	fmt.Printf("reset token for %s (valid until %s):\n%s\n", target.Username, reset.ExpiresAt.Format("2006-01-02 15:04:05 MST"), reset.Token)
+}
+
+func findUser(username string) *models.User {
+	var found models.User
+	if err := db.DB.Where("username = ?", username).First(&found).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Fatalf("User %s not found", username)
+		}
+		log.Fatalf("Failed to fetch user: %v", err)
+	}
+	return &found
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/ChayanDass/beneficiary-manager/pkg/middleware"
 	"github.com/ChayanDass/beneficiary-manager/pkg/models"
@@ -42,19 +44,121 @@ func Router() *gin.Engine {
 			scheme.GET("/status/:id", GetSchemeStatus) // Fetch scheme status
 		}
 
+		// Scheme Routes for applicants
+		schemeApplicant := api.Group("/schemes")
+		schemeApplicant.Use(middleware.Authenticate(), middleware.RequirePermission(models.PermissionApplicationOwn))
+		{
+			schemeApplicant.GET("/eligible", GetEligibleSchemes) // Schemes the caller is eligible for
+		}
+
+		// Scheme Admin Routes
+		schemeAdmin := api.Group("/schemes")
+		schemeAdmin.Use(middleware.Authenticate(), middleware.RequirePermission(models.PermissionSchemeManage))
+		{
+			schemeAdmin.POST("", CreateScheme)         // Create scheme with eligibility
+			schemeAdmin.PUT("/:id", ReplaceScheme)     // Replace scheme with eligibility
+			schemeAdmin.PATCH("/:id", PatchScheme)     // Partially update scheme
+			schemeAdmin.DELETE("/:id", DeleteScheme)   // Soft-delete scheme
+			schemeAdmin.POST("/import", ImportSchemes) // Upsert schemes from a JSON, YAML or CSV file
+			schemeAdmin.GET("/export", ExportSchemes)  // Download all schemes as JSON, YAML or CSV
+		}
+
 		// Application Routes
 		application := api.Group("/applications")
-		application.Use(middleware.BasicAuth())
+		application.Use(middleware.Authenticate(), middleware.RequirePermission(models.PermissionApplicationOwn))
 		{
-			application.POST("/", SubmitApplication)                       // Submit application
-			application.GET("/", GetApplications)                          // Get application status
-			application.POST("/withdraw-application", WithdrawApplication) // Submit application without user ID
-			application.POST("/init-application", InitApplication)         // Initialize application
-			application.PUT("/:id", ModifyApplication)                     // Update application
-			application.GET("/status/:id", GetApplicationStatus)           // Get application by ID
+			application.POST("/", SubmitApplication)                                       // Submit application
+			application.GET("/", GetApplications)                                          // Get application status
+			application.POST("/withdraw-application", WithdrawApplication)                 // Submit application without user ID
+			application.POST("/init-application", InitApplication)                         // Initialize application
+			application.PUT("/:id", ModifyApplication)                                     // Update application
+			application.PATCH("/:id", PatchApplication)                                    // Merge-patch or JSON Patch a draft
+			application.GET("/status/:id", GetApplicationStatus)                           // Get application by ID
+			application.GET("/eligibility/:id", CheckApplicationEligibility)               // Dry-run eligibility check
+			application.GET("/history/:id", GetApplicationHistory)                         // Get application status timeline
+			application.GET("/:id/documents", ListApplicationDocuments)                    // List attached documents
+			application.POST("/:id/documents", AddApplicationDocument)                     // Attach a document by URL
+			application.PUT("/:id/documents/:doc_id", ReuploadDocument)                    // Replace an uploaded document
+			application.PUT("/:id/documents/:doc_id/metadata", UpdateApplicationDocument)  // Rename a document or change its URL
+			application.DELETE("/:id/documents/:doc_id", DeleteApplicationDocument)        // Remove a document
+			application.POST("/:id/documents/upload", UploadDocumentFile)                  // Upload a document file
+			application.GET("/:id/addresses", ListApplicationAddresses)                    // List addresses
+			application.POST("/:id/addresses", AddApplicationAddress)                      // Add an address
+			application.PUT("/:id/addresses/:address_id", UpdateApplicationAddress)        // Replace an address
+			application.DELETE("/:id/addresses/:address_id", DeleteApplicationAddress)     // Remove an address
+			application.GET("/:id/education", ListApplicationEducation)                    // List education history
+			application.POST("/:id/education", AddApplicationEducation)                    // Add an education entry
+			application.PUT("/:id/education/:education_id", UpdateApplicationEducation)    // Replace an education entry
+			application.DELETE("/:id/education/:education_id", DeleteApplicationEducation) // Remove an education entry
+			application.GET("/:id/snapshots", GetApplicationSnapshots)                     // List submission snapshots
+			application.GET("/:id/snapshots/diff", DiffApplicationSnapshots)               // Compare two submissions
+			application.GET("/:id/snapshots/:version", GetApplicationSnapshot)             // Get one submission snapshot
 
 		}
 
+		// File Routes, authorized by signed links
+		api.GET("/files/*key", DownloadFile)
+
+		// Beckn BPP Routes, answered asynchronously through on_* callbacks
+		becknRoutes := api.Group("/beckn")
+		becknRoutes.Use(middleware.BecknSignatureAuth())
+		{
+			becknRoutes.POST("/search", BecknSearch)   // Discover schemes
+			becknRoutes.POST("/select", BecknSelect)   // Get scheme details and quote
+			becknRoutes.POST("/init", BecknInit)       // Initialize draft application
+			becknRoutes.POST("/confirm", BecknConfirm) // Submit application
+			becknRoutes.POST("/status", BecknStatus)   // Get application status
+			becknRoutes.POST("/cancel", BecknCancel)   // Withdraw application
+		}
+
+		// Auth Routes
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", Register)             // Self-service signup
+			auth.POST("/login", Login)                   // Issue access and refresh tokens
+			auth.POST("/refresh", RefreshToken)          // Rotate refresh token
+			auth.POST("/logout", Logout)                 // Revoke refresh token
+			auth.GET("/availability", CheckAvailability) // Check username/email availability
+			auth.POST("/password-reset", ResetPassword)  // Set a new password with a reset token
+		}
+
+		// User Routes
+		user := api.Group("/users")
+		user.Use(middleware.Authenticate())
+		{
+			user.GET("/me", GetMe)                                                                                  // Get own account
+			user.DELETE("/me", DeactivateMe)                                                                        // Deactivate own account
+			user.PUT("/me/password", ChangePassword)                                                                // Change own password
+			user.GET("/me/profile", middleware.RequirePermission(models.PermissionApplicationOwn), GetMyProfile)    // Get saved applicant profile
+			user.PUT("/me/profile", middleware.RequirePermission(models.PermissionApplicationOwn), UpdateMyProfile) // Update saved applicant profile
+		}
+
+		// Reviewer Routes
+		review := api.Group("/review")
+		review.Use(middleware.Authenticate(), middleware.RequirePermission(models.PermissionApplicationReview))
+		{
+			review.GET("/applications", ListReviewQueue)                                   // List submitted applications
+			review.GET("/applications/:id", GetApplicationForReview)                       // Get any application for review
+			review.POST("/applications/:id/assign", AssignApplication)                     // Assign to a reviewer
+			review.POST("/applications/:id/claim", ClaimApplication)                       // Claim an unassigned case
+			review.POST("/applications/:id/release", ReleaseApplication)                   // Release a claimed case
+			review.POST("/applications/:id/status", TransitionApplicationStatus)           // Move application through review
+			review.GET("/applications/:id/history", GetApplicationHistoryForReview)        // Get application status timeline
+			review.PUT("/applications/:id/documents/:doc_id/verification", VerifyDocument) // Verify or reject a document
+			review.GET("/applications/:id/snapshots", GetSnapshotsForReview)               // List submission snapshots
+			review.GET("/applications/:id/snapshots/diff", DiffSnapshotsForReview)         // Compare two submissions
+			review.GET("/applications/:id/snapshots/:version", GetSnapshotForReview)       // Get one submission snapshot
+		}
+
+		// Admin Routes
+		admin := api.Group("/admin")
+		admin.Use(middleware.Authenticate(), middleware.RequirePermission(models.PermissionUserManage))
+		{
+			admin.PUT("/users/:id/role", SetUserRole)                   // Assign a role to a user
+			admin.POST("/users/:id/password-reset", IssuePasswordReset) // Issue a password reset token
+			admin.DELETE("/users/:id", DeactivateUser)                  // Deactivate a user
+		}
+
 	}
 	return r
 }
@@ -67,3 +171,18 @@ func HandleInvalidUrl(c *gin.Context) {
 	}
 	c.JSON(http.StatusNotFound, er)
 }
+
+// pathID reads the numeric id path parameter, writing a 400 response if it is not a positive
+// number. kind names the resource in the message.
+func pathID(c *gin.Context, kind string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid " + kind + " ID",
+			Error:   fmt.Sprintf("invalid id %q", c.Param("id")),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
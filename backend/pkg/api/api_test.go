@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// createUser stores a user with the given role.
+func createUser(t *testing.T, username string, role models.Role) *models.User {
+	t.Helper()
+	user, err := utils.CreateUser(db.DB, username, username+"@example.org", "correct horse", role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// callAPI sends a request through the API router, authenticated as user unless it is nil, and
+// decodes the data of the response into out unless it is nil.
+func callAPI(t *testing.T, user *models.User, method, path string, body, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	gin.SetMode(gin.TestMode)
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, "/api/v1"+path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if user != nil {
+		token, _, err := utils.IssueAccessToken(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	Router().ServeHTTP(w, req)
+
+	if out != nil && w.Code < http.StatusBadRequest {
+		response := struct {
+			Data interface{} `json:"data"`
+		}{Data: out}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid response %s: %v", w.Body.String(), err)
+		}
+	}
+	return w
+}
+
+// Denied requests are answered by the route guards before any handler touches the database.
+func TestRouteGuards(t *testing.T) {
+	applicant := &models.User{ID: 1, Username: "asha", Role: models.RoleApplicant}
+	reviewer := &models.User{ID: 2, Username: "ravi", Role: models.RoleReviewer}
+	admin := &models.User{ID: 3, Username: "root", Role: models.RoleAdmin}
+
+	tests := []struct {
+		user   *models.User
+		method string
+		path   string
+		want   int
+	}{
+		{nil, http.MethodPost, "/schemes", http.StatusUnauthorized},
+		{applicant, http.MethodPost, "/schemes", http.StatusForbidden},
+		{reviewer, http.MethodDelete, "/schemes/1", http.StatusForbidden},
+		{applicant, http.MethodGet, "/review/applications", http.StatusForbidden},
+		{admin, http.MethodGet, "/applications/", http.StatusForbidden},
+		{reviewer, http.MethodGet, "/applications/", http.StatusForbidden},
+		{applicant, http.MethodPut, "/admin/users/1/role", http.StatusForbidden},
+		{reviewer, http.MethodPost, "/admin/users/1/password-reset", http.StatusForbidden},
+		{nil, http.MethodGet, "/users/me", http.StatusUnauthorized},
+		{reviewer, http.MethodGet, "/users/me/profile", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		who := "anonymous"
+		if tt.user != nil {
+			who = string(tt.user.Role)
+		}
+		if w := callAPI(t, tt.user, tt.method, tt.path, nil, nil); w.Code != tt.want {
+			t.Errorf("%s %s as %s returned %d, want %d", tt.method, tt.path, who, w.Code, tt.want)
+		}
+	}
+}
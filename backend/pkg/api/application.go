@@ -1,7 +1,9 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -86,6 +88,7 @@ func GetApplications(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse "Invalid request or application is incomplete"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
 // @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application cannot be submitted in its current state or scheme is not open"
 // @Failure 500 {object} models.ErrorResponse "Failed to submit application"
 // @Router /applications/submit [post]
 func SubmitApplication(c *gin.Context) {
@@ -107,13 +110,7 @@ func SubmitApplication(c *gin.Context) {
 	userID := userIDVal.(uint)
 
 	var application models.Application
-	err := db.DB.
-		Preload("User").
-		Preload("Scheme").
-		Preload("StudentProfile").
-		Preload("StudentProfile.Documents").
-		Preload("StudentProfile.EducationHistory").
-		Preload("StudentProfile.Addresses").
+	err := utils.PreloadApplicationDetails(db.DB).
 		Where("id = ? AND user_id = ?", req.ApplicationID, userID).
 		First(&application).Error
 
@@ -122,36 +119,35 @@ func SubmitApplication(c *gin.Context) {
 		return
 	}
 
-	if !application.IsDraft {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusFound,
-			Message: "Application is already submitted",
-			Error:   "Application is already submitted",
-		})
-		return
-	}
-	now := time.Now()
-	application.IsDraft = false
-	application.Status = "submitted"
-	application.SubmittedAt = &now
-
-	// ✅ Check completeness before submission
-	if err := utils.CheckApplicationCompleteness(&application); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Application is incomplete",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	if err := db.DB.
-		Save(&application).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to submit application",
-			Error:   err.Error(),
-		})
+	if err := utils.SubmitApplication(db.DB, &application, userID); err != nil {
+		var submissionErr *utils.SubmissionError
+		switch {
+		case errors.As(err, &submissionErr):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: submissionErr.Message,
+				Error:   submissionErr.Error(),
+				Details: submissionErr.Details,
+			})
+		case errors.Is(err, models.ErrIllegalTransition):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Application cannot be submitted in its current state",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrSchemeNotOpen):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Scheme is not open for applications",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to submit application",
+				Error:   err.Error(),
+			})
+		}
 		return
 	}
 
@@ -170,15 +166,16 @@ func SubmitApplication(c *gin.Context) {
 // WithdrawApplication withdraws a submitted application for the authenticated user.
 //
 // @Summary Withdraw application
-// @Description Withdraws a submitted application and marks it as a draft.
+// @Description Withdraws a submitted application so it can be edited and submitted again.
 // @Tags Applications
 // @Accept json
 // @Produce json
 // @Param request body models.SubmitExistingApplicationRequest true "Withdraw application request"
 // @Success 200 {object} models.SuccessResponse "Application withdrawn successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid request or application cannot be withdrawn"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
 // @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application cannot be withdrawn in its current state"
 // @Failure 500 {object} models.ErrorResponse "Failed to withdraw application"
 // @Router /applications/withdraw [post]
 func WithdrawApplication(c *gin.Context) {
@@ -209,21 +206,15 @@ func WithdrawApplication(c *gin.Context) {
 		return
 	}
 
-	if application.IsDraft {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Draft applications cannot be withdrawn"})
-		return
-	}
-
-	if application.SubmittedAt == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Application not submitted"})
-		return
-	}
-
-	application.IsDraft = true
-	application.Status = "draft"
-	application.SubmittedAt = nil
-
-	if err := db.DB.Save(&application).Error; err != nil {
+	if err := utils.TransitionApplication(db.DB, &application, models.ApplicationStatusWithdrawn, models.PermissionApplicationOwn, userID, ""); err != nil {
+		if errors.Is(err, models.ErrIllegalTransition) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Application cannot be withdrawn in its current state",
+				Error:   err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
 		return
 	}
@@ -248,7 +239,7 @@ func WithdrawApplication(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
 // @Failure 404 {object} models.ErrorResponse "Scheme not found"
-// @Failure 409 {object} models.ErrorResponse "Application already exists"
+// @Failure 409 {object} models.ErrorResponse "Application already exists or scheme is not open"
 // @Failure 500 {object} models.ErrorResponse "Failed to initialize application"
 // @Router /applications/init [post]
 func InitApplication(c *gin.Context) {
@@ -269,83 +260,58 @@ func InitApplication(c *gin.Context) {
 	}
 	userID := userIDVal.(uint)
 
-	// Optional: Check if there's already a draft application for this user & scheme
-	var existing models.Application
-	if err := db.DB.
-		Where("user_id = ? AND scheme_id = ? ", userID, req.SchemeID).
-		First(&existing).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": " application already exists"})
-		return
-	}
-	var schema models.Scheme
-	// Check if the scheme exists
-	if err := db.DB.First(&schema, req.SchemeID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	if _, err := utils.InitApplication(db.DB, userID, req.SchemeID, nil); err != nil {
+		switch {
+		case errors.Is(err, utils.ErrApplicationExists):
+			c.JSON(http.StatusConflict, gin.H{"error": " application already exists"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Code:    http.StatusNotFound,
 				Message: "Scheme not found",
 				Error:   err.Error(),
 			})
-		} else {
+		case errors.Is(err, utils.ErrSchemeNotOpen):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Scheme is not open for applications",
+				Error:   err.Error(),
+			})
+		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Code:    http.StatusInternalServerError,
-				Message: "Failed to fetch scheme",
+				Message: "Failed to initialize application",
 				Error:   err.Error(),
 			})
 		}
 		return
 	}
 
-	// Continue with the logic after checking for the scheme
-
-	student := models.StudentProfile{
-		UserID:   userID,
-		FullName: "Unknown", // Use default or empty values
-		// ... other defaults
-	}
-	if err := db.DB.Create(&student).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create student profile"})
-		return
-	}
-
-	application := models.Application{
-		UserID:           userID,
-		SchemeID:         req.SchemeID,
-		IsDraft:          true,
-		StudentProfileID: student.ID,
-		Status:           "draft",
-	}
-
-	if err := db.DB.Create(&application).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to initialize application",
-			Error:   err.Error(),
-		})
-		return
-	}
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Application initialized successfully",
 	})
 }
 
+// maxPatchSize limits the body of PATCH /applications/{id}.
+const maxPatchSize = 1 << 20
+
 // ModifyApplication modifies an existing draft application for the authenticated user.
 //
 // @Summary Modify application
 // @Description Updates the details of a draft application, including the student profile and related data.
+// @Description The update is applied completely or not at all; invalid fields are listed in details.
+// @Description Empty fields are left unchanged; use PATCH to clear them.
 // @Tags Applications
 // @Accept json
 // @Produce json
 // @Param id path string true "Application ID"
 // @Param request body models.StudentProfileInput true "Modify application request"
-// @Success 200 {object} models.SuccessResponse "Application modified successfully"
-// @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Success 200 {object} models.SuccessResponse{data=models.Application} "Application modified successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
-// @Failure 403 {object} models.ErrorResponse "Cannot modify application, it is already submitted"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
 // @Failure 404 {object} models.ErrorResponse "Application not found"
 // @Failure 500 {object} models.ErrorResponse "Failed to update application"
-// @Router /applications/{id}/modify [put]
+// @Router /applications/{id} [put]
 func ModifyApplication(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -365,100 +331,174 @@ func ModifyApplication(c *gin.Context) {
 		return
 	}
 
-	schemeID := c.Param("id")
-
-	var application models.Application
-	if err := db.DB.
-		Preload("User").
-		Preload("Scheme").
-		Preload("StudentProfile").
-		Preload("StudentProfile.Documents").
-		Preload("StudentProfile.EducationHistory").
-		Preload("StudentProfile.Addresses").
-		Where("user_id = ? AND id = ?", userIDUint, schemeID).
-		First(&application).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-		return
-	}
-
-	studentProfile := application.StudentProfile
-
 	var input models.StudentProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input",
+			Error:   err.Error(),
+		})
 		return
 	}
 
-	if !application.IsDraft {
-		c.JSON(http.StatusForbidden,
-			models.ErrorResponse{
+	application, err := utils.ModifyApplication(db.DB, userIDUint, c.Param("id"), input)
+	if err != nil {
+		var invalid utils.ValidationErrors
+		switch {
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid input",
+				Error:   "one or more fields are invalid",
+				Details: invalid,
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Application not found",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrApplicationNotEditable):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
 				Code:    http.StatusForbidden,
-				Message: "Cannot modify application, it is already submitted.",
-				Error:   "Application is already submitted",
+				Message: "Cannot modify application in its current state.",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update application",
+				Error:   err.Error(),
 			})
+		}
 		return
 	}
 
-	// --- Update student profile fields ---
-	if input.FullName != "" {
-		studentProfile.FullName = input.FullName
-	}
-	if input.Email != "" {
-		studentProfile.Email = input.Email
-	}
-	if input.PhoneNumber != "" {
-		studentProfile.PhoneNumber = input.PhoneNumber
-	}
-	if input.DateOfBirth != nil {
-		studentProfile.DateOfBirth = *input.DateOfBirth
-	}
-	if input.Qualification != "" {
-		studentProfile.Qualification = input.Qualification
-	}
-	if input.Category != "" {
-		studentProfile.Category = input.Category
-	}
-	if input.Income != nil {
-		studentProfile.Income = *input.Income
-	}
-	if input.Nationality != "" {
-		studentProfile.Nationality = input.Nationality
-	}
-	if input.Gender != "" {
-		studentProfile.Gender = input.Gender
-	}
-	if input.AadhaarNumber != "" {
-		studentProfile.AadhaarNumber = input.AadhaarNumber
-	}
-	if err := db.DB.Omit("Documents", "Addresses", "EducationHistory").Save(&studentProfile).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update student profile"})
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application modified successfully",
+		Data:    application,
+	})
+}
+
+// PatchApplication partially updates a draft application for the authenticated user.
+//
+// @Summary Patch application
+// @Description Applies a JSON Merge Patch (application/merge-patch+json, RFC 7396) or a JSON Patch
+// @Description (application/json-patch+json, RFC 6902) to the application's profile, which has the shape of
+// @Description models.StudentProfileInput. Null or a removed member clears the field; documents, addresses and
+// @Description education entries missing from the result are removed. Plain application/json is read as a merge patch.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body []models.JSONPatchOperation true "Merge patch object or JSON Patch operations"
+// @Success 200 {object} models.SuccessResponse{data=models.Application} "Application modified successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid patch or input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Patch test failed"
+// @Failure 413 {object} models.ErrorResponse "Patch too large"
+// @Failure 415 {object} models.ErrorResponse "Unsupported patch format"
+// @Failure 500 {object} models.ErrorResponse "Failed to update application"
+// @Router /applications/{id} [patch]
+func PatchApplication(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
 		return
 	}
 
-	if err := utils.UpsertStudentDocuments(db.DB, studentProfile.ID, input.Documents); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	format := utils.PatchFormat(c.ContentType())
+	switch format {
+	case utils.PatchFormatMerge, utils.PatchFormatJSON:
+	case "application/json":
+		format = utils.PatchFormatMerge
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Unsupported patch format",
+			Error:   "use " + string(utils.PatchFormatMerge) + " or " + string(utils.PatchFormatJSON),
+		})
 		return
 	}
-	if err := utils.UpsertStudentAddresses(db.DB, studentProfile.ID, input.Addresses); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to upsert address",
-			"details": err.Error(),
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: "Patch too large",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid patch",
+			Error:   err.Error(),
 		})
 		return
 	}
-	if err := utils.UpsertEducationHistory(db.DB, studentProfile.ID, input.EducationHistory); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+	application, removed, err := utils.PatchApplication(db.DB, userIDVal.(uint), c.Param("id"), patch, format)
+	if err != nil {
+		var invalid utils.ValidationErrors
+		switch {
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid input",
+				Error:   "one or more fields are invalid",
+				Details: invalid,
+			})
+		case errors.Is(err, utils.ErrInvalidPatch):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid patch",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrPatchTestFailed):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Patch test failed",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Application not found",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrApplicationNotEditable):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Cannot modify application in its current state.",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update application",
+				Error:   err.Error(),
+			})
+		}
 		return
 	}
 
-	res := models.SuccessResponse{
-		Code:    http.StatusOK,
-		Message: "Application modified successfully",
-		Data:    &application,
+	for _, document := range removed {
+		if document.StorageKey != "" {
+			discardObject(c, document.StorageKey)
+		}
 	}
-	// Return the success response
 
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application modified successfully",
+		Data:    application,
+	})
 }
 
 // GetApplicationStatus retrieves the status of a specific application for the authenticated user.
@@ -495,3 +535,213 @@ func GetApplicationStatus(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, res)
 }
+
+// CheckApplicationEligibility evaluates an application against its scheme without submitting it.
+//
+// @Summary Check application eligibility
+// @Description Dry-run of the eligibility check performed at submission. Returns the pass/fail result of every criterion.
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=models.EligibilityReport} "Eligibility evaluated successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Router /applications/eligibility/{id} [get]
+func CheckApplicationEligibility(c *gin.Context) {
+	applicationID := c.Param("id")
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var application models.Application
+	if err := db.DB.
+		Preload("Scheme").
+		Preload("Scheme.Eligibility").
+		Preload("StudentProfile").
+		Where("id = ? AND user_id = ?", applicationID, userID).
+		First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Application not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	report := utils.EvaluateEligibility(&application.StudentProfile, &application.Scheme, time.Now())
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Eligibility evaluated successfully",
+		Data:    report,
+	})
+}
+
+// GetApplicationHistory retrieves the status timeline of an application owned by the authenticated user.
+//
+// @Summary Get application history
+// @Description Returns every status change of the application in chronological order.
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.ApplicationStatusHistory} "Application history fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch application history"
+// @Router /applications/history/{id} [get]
+func GetApplicationHistory(c *gin.Context) {
+	if application, ok := findOwnApplication(c); ok {
+		respondStatusHistory(c, application.ID)
+	}
+}
+
+// findOwnApplication loads the application named by the id path parameter if it belongs to the
+// authenticated user, writing a 401 or 404 response if it cannot.
+func findOwnApplication(c *gin.Context) (*models.Application, bool) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return nil, false
+	}
+
+	var application models.Application
+	if err := db.DB.
+		Where("id = ? AND user_id = ?", c.Param("id"), userIDVal.(uint)).
+		First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Application not found",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	return &application, true
+}
+
+// respondStatusHistory writes the status timeline of an application as the response.
+func respondStatusHistory(c *gin.Context, applicationID uint) {
+	var history []models.ApplicationStatusHistory
+	if err := db.DB.
+		Where("application_id = ?", applicationID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch application history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application history fetched successfully",
+		Data:    history,
+	})
+}
+
+// ReuploadDocument replaces the file of an uploaded document.
+//
+// @Summary Re-upload document
+// @Description Replaces the URL of an uploaded document and resets it to pending verification. While the application is editable any document can be replaced; after submission only rejected documents can.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param doc_id path string true "Document ID"
+// @Param request body models.ReuploadDocumentRequest true "New document URL"
+// @Success 200 {object} models.SuccessResponse{data=models.UploadDocument} "Document re-uploaded successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
+// @Failure 403 {object} models.ErrorResponse "Document cannot be replaced in the current state"
+// @Failure 404 {object} models.ErrorResponse "Application or document not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to re-upload document"
+// @Router /applications/{id}/documents/{doc_id} [put]
+func ReuploadDocument(c *gin.Context) {
+	var req models.ReuploadDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var application models.Application
+	if err := db.DB.
+		Where("id = ? AND user_id = ?", c.Param("id"), userID).
+		First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Application not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var document models.UploadDocument
+	if err := db.DB.
+		Where("id = ? AND student_id = ?", c.Param("doc_id"), application.StudentProfileID).
+		First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Document not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !application.IsDraft && document.VerificationStatus != models.DocumentRejected {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Only rejected documents can be replaced after submission",
+			Error:   "document is " + string(document.VerificationStatus),
+		})
+		return
+	}
+
+	previousKey := document.StorageKey
+	document.URL = req.URL
+	document.StorageKey = ""
+	document.ContentType = ""
+	document.Size = 0
+	document.Checksum = ""
+	document.VerificationStatus = models.DocumentPending
+	document.RejectionReason = ""
+	document.VerifiedByID = nil
+	document.VerifiedAt = nil
+
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("StudentProfile").Save(&document).Error; err != nil {
+			return err
+		}
+		return utils.RefreshApplicationVerified(tx, &application)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to re-upload document",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if previousKey != "" {
+		discardObject(c, previousKey)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Document re-uploaded successfully",
+		Data:    document,
+	})
+}
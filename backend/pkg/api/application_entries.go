@@ -0,0 +1,447 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListApplicationDocuments lists the documents attached to an application owned by the authenticated user.
+//
+// @Summary List application documents
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.UploadDocument} "Documents fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch documents"
+// @Router /applications/{id}/documents [get]
+func ListApplicationDocuments(c *gin.Context) {
+	var documents []models.UploadDocument
+	listProfileEntries(c, &documents, "Documents")
+}
+
+// AddApplicationDocument attaches a document to a draft application.
+//
+// @Summary Attach document
+// @Description Attaches a document by URL. Use /applications/{id}/documents/upload to upload the file itself.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body models.DocumentInput true "Document"
+// @Success 201 {object} models.SuccessResponse{data=models.UploadDocument} "Document added successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Document already exists"
+// @Failure 500 {object} models.ErrorResponse "Failed to add document"
+// @Router /applications/{id}/documents [post]
+func AddApplicationDocument(c *gin.Context) {
+	var input models.DocumentInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	editProfileEntry(c, http.StatusCreated, "Document", "add", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		document, err := utils.CreateStudentDocument(tx, app.StudentProfileID, input)
+		if err != nil {
+			return nil, err
+		}
+		return document, utils.RefreshApplicationVerified(tx, app)
+	})
+}
+
+// UpdateApplicationDocument renames a document of a draft application or points it at another URL.
+//
+// @Summary Update document
+// @Description Changes the name of a document and, when a url is given, the file it points at. A new URL has to be verified again. Use PUT /applications/{id}/documents/{doc_id} to re-upload a document after submission.
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param doc_id path int true "Document ID"
+// @Param request body models.DocumentInput true "Document"
+// @Success 200 {object} models.SuccessResponse{data=models.UploadDocument} "Document updated successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or document not found"
+// @Failure 409 {object} models.ErrorResponse "Document already exists"
+// @Failure 500 {object} models.ErrorResponse "Failed to update document"
+// @Router /applications/{id}/documents/{doc_id}/metadata [put]
+func UpdateApplicationDocument(c *gin.Context) {
+	id, ok := entryID(c, "doc_id", "Document")
+	if !ok {
+		return
+	}
+	var input models.DocumentInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	var replacedKey string
+	editProfileEntry(c, http.StatusOK, "Document", "update", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		document, key, err := utils.UpdateStudentDocument(tx, app.StudentProfileID, id, input)
+		if err != nil {
+			return nil, err
+		}
+		replacedKey = key
+		return document, utils.RefreshApplicationVerified(tx, app)
+	})
+	if replacedKey != "" {
+		discardObject(c, replacedKey)
+	}
+}
+
+// DeleteApplicationDocument removes a document from a draft application.
+//
+// @Summary Remove document
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param doc_id path int true "Document ID"
+// @Success 200 {object} models.SuccessResponse{data=models.UploadDocument} "Document removed successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or document not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to remove document"
+// @Router /applications/{id}/documents/{doc_id} [delete]
+func DeleteApplicationDocument(c *gin.Context) {
+	id, ok := entryID(c, "doc_id", "Document")
+	if !ok {
+		return
+	}
+	var removed *models.UploadDocument
+	editProfileEntry(c, http.StatusOK, "Document", "remove", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		document, err := utils.DeleteStudentDocument(tx, app.StudentProfileID, id)
+		if err != nil {
+			return nil, err
+		}
+		removed = document
+		return document, utils.RefreshApplicationVerified(tx, app)
+	})
+	if removed != nil && removed.StorageKey != "" {
+		discardObject(c, removed.StorageKey)
+	}
+}
+
+// ListApplicationAddresses lists the addresses of an application owned by the authenticated user.
+//
+// @Summary List application addresses
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.Address} "Addresses fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch addresses"
+// @Router /applications/{id}/addresses [get]
+func ListApplicationAddresses(c *gin.Context) {
+	var addresses []models.Address
+	listProfileEntries(c, &addresses, "Addresses")
+}
+
+// AddApplicationAddress adds an address to a draft application.
+//
+// @Summary Add address
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body models.AddressInput true "Address"
+// @Success 201 {object} models.SuccessResponse{data=models.Address} "Address added successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Address already exists"
+// @Failure 500 {object} models.ErrorResponse "Failed to add address"
+// @Router /applications/{id}/addresses [post]
+func AddApplicationAddress(c *gin.Context) {
+	var input models.AddressInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	editProfileEntry(c, http.StatusCreated, "Address", "add", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return utils.CreateStudentAddress(tx, app.StudentProfileID, input)
+	})
+}
+
+// UpdateApplicationAddress replaces an address of a draft application.
+//
+// @Summary Update address
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param address_id path int true "Address ID"
+// @Param request body models.AddressInput true "Address"
+// @Success 200 {object} models.SuccessResponse{data=models.Address} "Address updated successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or address not found"
+// @Failure 409 {object} models.ErrorResponse "Address already exists"
+// @Failure 500 {object} models.ErrorResponse "Failed to update address"
+// @Router /applications/{id}/addresses/{address_id} [put]
+func UpdateApplicationAddress(c *gin.Context) {
+	id, ok := entryID(c, "address_id", "Address")
+	if !ok {
+		return
+	}
+	var input models.AddressInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	editProfileEntry(c, http.StatusOK, "Address", "update", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return utils.UpdateStudentAddress(tx, app.StudentProfileID, id, input)
+	})
+}
+
+// DeleteApplicationAddress removes an address from a draft application.
+//
+// @Summary Remove address
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param address_id path int true "Address ID"
+// @Success 200 {object} models.SuccessResponse "Address removed successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or address not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to remove address"
+// @Router /applications/{id}/addresses/{address_id} [delete]
+func DeleteApplicationAddress(c *gin.Context) {
+	id, ok := entryID(c, "address_id", "Address")
+	if !ok {
+		return
+	}
+	editProfileEntry(c, http.StatusOK, "Address", "remove", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return nil, utils.DeleteStudentAddress(tx, app.StudentProfileID, id)
+	})
+}
+
+// ListApplicationEducation lists the education history of an application owned by the authenticated user.
+//
+// @Summary List application education history
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.StudentAcademicQualification} "Education history fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch education history"
+// @Router /applications/{id}/education [get]
+func ListApplicationEducation(c *gin.Context) {
+	var history []models.StudentAcademicQualification
+	listProfileEntries(c, &history, "Education history")
+}
+
+// AddApplicationEducation adds an entry to the education history of a draft application.
+//
+// @Summary Add education entry
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body models.EducationHistoryInput true "Education entry"
+// @Success 201 {object} models.SuccessResponse{data=models.StudentAcademicQualification} "Education entry added successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to add education entry"
+// @Router /applications/{id}/education [post]
+func AddApplicationEducation(c *gin.Context) {
+	var input models.EducationHistoryInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	editProfileEntry(c, http.StatusCreated, "Education entry", "add", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return utils.CreateEducationEntry(tx, app.StudentProfileID, input)
+	})
+}
+
+// UpdateApplicationEducation replaces an entry of the education history of a draft application.
+//
+// @Summary Update education entry
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param education_id path int true "Education entry ID"
+// @Param request body models.EducationHistoryInput true "Education entry"
+// @Success 200 {object} models.SuccessResponse{data=models.StudentAcademicQualification} "Education entry updated successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or education entry not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to update education entry"
+// @Router /applications/{id}/education/{education_id} [put]
+func UpdateApplicationEducation(c *gin.Context) {
+	id, ok := entryID(c, "education_id", "Education entry")
+	if !ok {
+		return
+	}
+	var input models.EducationHistoryInput
+	if !bindEntryInput(c, &input) {
+		return
+	}
+	editProfileEntry(c, http.StatusOK, "Education entry", "update", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return utils.UpdateEducationEntry(tx, app.StudentProfileID, id, input)
+	})
+}
+
+// DeleteApplicationEducation removes an entry from the education history of a draft application.
+//
+// @Summary Remove education entry
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param education_id path int true "Education entry ID"
+// @Success 200 {object} models.SuccessResponse "Education entry removed successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Cannot modify application in its current state"
+// @Failure 404 {object} models.ErrorResponse "Application or education entry not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to remove education entry"
+// @Router /applications/{id}/education/{education_id} [delete]
+func DeleteApplicationEducation(c *gin.Context) {
+	id, ok := entryID(c, "education_id", "Education entry")
+	if !ok {
+		return
+	}
+	editProfileEntry(c, http.StatusOK, "Education entry", "remove", func(tx *gorm.DB, app *models.Application) (interface{}, error) {
+		return nil, utils.DeleteEducationEntry(tx, app.StudentProfileID, id)
+	})
+}
+
+// listProfileEntries writes the documents, addresses or education history of an application
+// owned by the authenticated user, oldest first, as the response.
+func listProfileEntries(c *gin.Context, entries interface{}, kind string) {
+	application, ok := findOwnApplication(c)
+	if !ok {
+		return
+	}
+	if err := db.DB.
+		Where("student_id = ?", application.StudentProfileID).
+		Order("id ASC").
+		Find(entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch " + strings.ToLower(kind),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: kind + " fetched successfully",
+		Data:    entries,
+	})
+}
+
+var entryActionsDone = map[string]string{"add": "added", "update": "updated", "remove": "removed"}
+
+// editProfileEntry runs fn on an editable application of the authenticated user in one
+// transaction and writes its result as the response. kind names the entry in messages and
+// action is what fn does: add, update or remove.
+func editProfileEntry(c *gin.Context, status int, kind, action string, fn func(tx *gorm.DB, app *models.Application) (interface{}, error)) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+
+	var result interface{}
+	applicationFound := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		application, err := utils.FindEditableApplication(tx, userIDVal.(uint), c.Param("id"))
+		if err != nil {
+			return err
+		}
+		applicationFound = true
+		result, err = fn(tx, application)
+		return err
+	})
+
+	var invalid utils.ValidationErrors
+	switch {
+	case err == nil:
+		c.JSON(status, models.SuccessResponse{
+			Code:    status,
+			Message: kind + " " + entryActionsDone[action] + " successfully",
+			Data:    result,
+		})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input",
+			Error:   "one or more fields are invalid",
+			Details: invalid,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		message := kind + " not found"
+		if !applicationFound {
+			message = "Application not found"
+		}
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: message,
+			Error:   err.Error(),
+		})
+	case errors.Is(err, utils.ErrApplicationNotEditable):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Cannot modify application in its current state.",
+			Error:   err.Error(),
+		})
+	case errors.Is(err, utils.ErrDuplicateEntry):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: kind + " already exists",
+			Error:   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to " + action + " " + strings.ToLower(kind),
+			Error:   err.Error(),
+		})
+	}
+}
+
+// bindEntryInput decodes the request body into input, writing a 400 response if it cannot.
+func bindEntryInput(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// entryID reads an entry ID path parameter, writing a 404 response if it is not a number.
+func entryID(c *gin.Context, param, kind string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: kind + " not found",
+			Error:   "invalid id " + c.Param(param),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+)
+
+// createCompleteApplication stores an applicant with a complete draft application for an open
+// scheme that requires an Aadhaar card and accepts a PAN card. Only the Aadhaar card is attached.
+func createCompleteApplication(t *testing.T, username string) (*models.User, *models.Application) {
+	t.Helper()
+	user := createUser(t, username, models.RoleApplicant)
+
+	now := time.Now()
+	scheme, err := utils.CreateScheme(db.DB, models.SchemeInput{
+		Name:      "Merit scholarship for " + username,
+		Amount:    5000,
+		StartDate: now.AddDate(0, -1, 0),
+		EndDate:   now.AddDate(1, 0, 0),
+		Status:    models.SchemeStatusOpen,
+		Eligibility: models.EligibilityInput{
+			Category: models.CategoryGeneral,
+			AgeMin:   18,
+			Documents: []models.EligibilityDocumentInput{
+				{Name: "aadhar_card", IsMandatory: true},
+				{Name: "pan_card"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dob := time.Date(2004, time.May, 17, 0, 0, 0, 0, time.UTC)
+	profile := models.StudentProfile{
+		UserID:        user.ID,
+		FullName:      "Asha Rao",
+		DateOfBirth:   &dob,
+		Gender:        "Female",
+		PhoneNumber:   "9876543210",
+		Qualification: "Class-XII",
+		Email:         username + "@example.org",
+		AadhaarNumber: "234123412346",
+		Nationality:   "Indian",
+		Category:      string(models.CategoryGeneral),
+		Income:        120000,
+		Documents: []models.UploadDocument{{
+			Name:               "aadhar_card",
+			URL:                "https://files.example.org/aadhar.pdf",
+			VerificationStatus: models.DocumentPending,
+		}},
+		EducationHistory: []models.StudentAcademicQualification{{Degree: "Class XII", University: "CBSE", YearOfPassing: 2022}},
+		Addresses: []models.Address{{
+			Type: "permanent", Street: "12 MG Road", City: "Bengaluru", State: "Karnataka", Pincode: "560001", Country: "India",
+		}},
+	}
+	if err := db.DB.Create(&profile).Error; err != nil {
+		t.Fatal(err)
+	}
+	application := models.Application{
+		UserID:           user.ID,
+		SchemeID:         scheme.ID,
+		StudentProfileID: profile.ID,
+		IsDraft:          true,
+		Status:           models.ApplicationStatusDraft,
+	}
+	if err := db.DB.Omit("User", "Scheme", "StudentProfile").Create(&application).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user, &application
+}
+
+// submitApplication submits an application through the API and returns the response.
+func submitApplication(t *testing.T, user *models.User, application *models.Application) (int, models.DocumentCheckReport) {
+	t.Helper()
+	w := callAPI(t, user, http.MethodPost, "/applications/", models.SubmitExistingApplicationRequest{ApplicationID: application.ID}, nil)
+	var response struct {
+		Details models.DocumentCheckReport `json:"details"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response.Details
+}
+
+func TestSubmitRequiresMandatoryDocuments(t *testing.T) {
+	useTestDB(t)
+	user, application := createCompleteApplication(t, "asha")
+
+	var aadhaar models.UploadDocument
+	if err := db.DB.Where("student_id = ? AND name = ?", application.StudentProfileID, "aadhar_card").First(&aadhaar).Error; err != nil {
+		t.Fatal(err)
+	}
+	// The profile keeps another document so that only the scheme requirement is missing
+	if _, err := utils.CreateStudentDocument(db.DB, application.StudentProfileID, models.DocumentInput{Name: "pan_card", URL: "https://files.example.org/pan.pdf"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DB.Model(&aadhaar).Update("verification_status", models.DocumentRejected).Error; err != nil {
+		t.Fatal(err)
+	}
+	code, report := submitApplication(t, user, application)
+	if code != http.StatusBadRequest || !reflect.DeepEqual(report.Missing, []string{"aadhar_card"}) {
+		t.Errorf("submit with a rejected Aadhaar card returned %d with %+v, want 400 with it missing", code, report)
+	}
+
+	if err := db.DB.Delete(&aadhaar).Error; err != nil {
+		t.Fatal(err)
+	}
+	code, report = submitApplication(t, user, application)
+	if code != http.StatusBadRequest || !reflect.DeepEqual(report.Missing, []string{"aadhar_card"}) {
+		t.Errorf("submit without an Aadhaar card returned %d with %+v, want 400 with it missing", code, report)
+	}
+}
+
+func TestSubmitRejectsUnknownDocuments(t *testing.T) {
+	useTestDB(t)
+	user, application := createCompleteApplication(t, "asha")
+
+	unknown := models.UploadDocument{StudentID: application.StudentProfileID, Name: "marksheet", URL: "https://files.example.org/marks.pdf"}
+	if err := db.DB.Omit("StudentProfile").Create(&unknown).Error; err != nil {
+		t.Fatal(err)
+	}
+	code, report := submitApplication(t, user, application)
+	if code != http.StatusBadRequest || !reflect.DeepEqual(report.Unknown, []string{"marksheet"}) {
+		t.Errorf("submit with an unknown document returned %d with %+v, want 400 naming it", code, report)
+	}
+}
+
+func TestSubmitApplication(t *testing.T) {
+	useTestDB(t)
+	user, application := createCompleteApplication(t, "asha")
+
+	if code, _ := submitApplication(t, user, application); code != http.StatusOK {
+		t.Fatalf("submit returned %d", code)
+	}
+	var stored models.Application
+	if err := db.DB.First(&stored, application.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.ApplicationStatusSubmitted || stored.IsDraft {
+		t.Errorf("submitted application is %s (draft %v)", stored.Status, stored.IsDraft)
+	}
+	if code, _ := submitApplication(t, user, application); code != http.StatusConflict {
+		t.Errorf("submitting twice returned %d, want 409", code)
+	}
+
+	other := createUser(t, "ravi", models.RoleApplicant)
+	if code, _ := submitApplication(t, other, application); code != http.StatusNotFound {
+		t.Errorf("submitting another user's application returned %d, want 404", code)
+	}
+}
+
+func TestApplicationHistory(t *testing.T) {
+	useTestDB(t)
+	user, application := createSubmittedApplication(t, "asha")
+	stranger := createUser(t, "kiran", models.RoleApplicant)
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+
+	if code := transition(t, reviewer, application, models.ApplicationStatusUnderReview, ""); code != http.StatusOK {
+		t.Fatalf("starting the review returned %d", code)
+	}
+	id := strconv.FormatUint(uint64(application.ID), 10)
+
+	var own []models.ApplicationStatusHistory
+	if w := callAPI(t, user, http.MethodGet, "/applications/history/"+id, nil, &own); w.Code != http.StatusOK {
+		t.Fatalf("owner history returned %d: %s", w.Code, w.Body.String())
+	}
+	if len(own) != 2 || own[0].FromStatus != "" || own[0].ToStatus != models.ApplicationStatusSubmitted ||
+		own[1].FromStatus != models.ApplicationStatusSubmitted || own[1].ToStatus != models.ApplicationStatusUnderReview {
+		t.Errorf("owner history = %+v, want submitted then under review", own)
+	}
+	if own[1].ActorID != reviewer.ID {
+		t.Errorf("review started by %d, want reviewer %d", own[1].ActorID, reviewer.ID)
+	}
+
+	var staff []models.ApplicationStatusHistory
+	if w := callAPI(t, reviewer, http.MethodGet, "/review/applications/"+id+"/history", nil, &staff); w.Code != http.StatusOK || !reflect.DeepEqual(staff, own) {
+		t.Errorf("review history returned %d with %+v, want %+v", w.Code, staff, own)
+	}
+	if w := callAPI(t, stranger, http.MethodGet, "/applications/history/"+id, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("another applicant's history returned %d, want 404", w.Code)
+	}
+	if w := callAPI(t, user, http.MethodGet, "/review/applications/"+id+"/history", nil, nil); w.Code != http.StatusForbidden {
+		t.Errorf("applicant reading the review history returned %d, want 403", w.Code)
+	}
+}
+
+func TestApplicationHistoryIsAppendOnly(t *testing.T) {
+	useTestDB(t)
+	_, application := createSubmittedApplication(t, "asha")
+
+	var entry models.ApplicationStatusHistory
+	if err := db.DB.Where("application_id = ?", application.ID).First(&entry).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DB.Model(&entry).Update("reason", "edited").Error; !errors.Is(err, models.ErrHistoryImmutable) {
+		t.Errorf("updating a history entry = %v, want ErrHistoryImmutable", err)
+	}
+	entry.ToStatus = models.ApplicationStatusApproved
+	if err := db.DB.Save(&entry).Error; !errors.Is(err, models.ErrHistoryImmutable) {
+		t.Errorf("saving a history entry = %v, want ErrHistoryImmutable", err)
+	}
+	if err := db.DB.Delete(&entry).Error; !errors.Is(err, models.ErrHistoryImmutable) {
+		t.Errorf("deleting a history entry = %v, want ErrHistoryImmutable", err)
+	}
+
+	var stored models.ApplicationStatusHistory
+	if err := db.DB.First(&stored, entry.ID).Error; err != nil {
+		t.Fatalf("history entry is gone: %v", err)
+	}
+	if stored.ToStatus != models.ApplicationStatusSubmitted || stored.Reason != "" {
+		t.Errorf("history entry changed to %+v", stored)
+	}
+}
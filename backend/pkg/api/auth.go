@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// Login exchanges a username and password for an access and refresh token.
+//
+// @Summary Login
+// @Description Verifies the credentials and issues a short-lived access token and a rotating refresh token.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "Credentials"
+// @Success 200 {object} models.SuccessResponse{data=models.TokenResponse} "Logged in successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Invalid credentials"
+// @Failure 403 {object} models.ErrorResponse "Account is deactivated"
+// @Failure 500 {object} models.ErrorResponse "Failed to log in"
+// @Router /auth/login [post]
+func Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := utils.AuthenticateUser(db.DB, req.Username, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUnknownUser), errors.Is(err, utils.ErrWrongPassword):
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid username or password",
+				Error:   "invalid credentials",
+			})
+		case errors.Is(err, utils.ErrAccountDeactivated):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Account is deactivated",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to log in",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	tokens, _, err := issueTokenPair(db.DB, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to log in",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Logged in successfully",
+		Data:    tokens,
+	})
+}
+
+// RefreshToken rotates a refresh token and issues a new access token.
+//
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new token pair. The presented refresh token is revoked; presenting a revoked token again revokes every refresh token of the user.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.SuccessResponse{data=models.TokenResponse} "Tokens refreshed successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Invalid or expired refresh token"
+// @Failure 500 {object} models.ErrorResponse "Failed to refresh tokens"
+// @Router /auth/refresh [post]
+func RefreshToken(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var tokens *models.TokenResponse
+	reused := false
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			Where("token_hash = ?", utils.HashToken(req.RefreshToken)).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidRefreshToken
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			// A rotated token was presented again, so it has leaked. Revocation is
+			// committed outside this transaction because this one is rolled back.
+			reused = true
+			return errInvalidRefreshToken
+		}
+		if time.Now().After(current.ExpiresAt) || !current.User.IsActive {
+			return errInvalidRefreshToken
+		}
+
+		var next *models.RefreshToken
+		var err error
+		tokens, next, err = issueTokenPair(tx, &current.User)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":     &now,
+			"replaced_by_id": next.ID,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidRefreshToken) {
+			if reused {
+				revokeTokenFamily(req.RefreshToken)
+			}
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "Invalid or expired refresh token",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to refresh tokens",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Tokens refreshed successfully",
+		Data:    tokens,
+	})
+}
+
+// Logout revokes a refresh token.
+//
+// @Summary Logout
+// @Description Revokes the given refresh token. Access tokens stay valid until they expire.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.SuccessResponse "Logged out successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 500 {object} models.ErrorResponse "Failed to log out"
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := db.DB.Model(&models.RefreshToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", utils.HashToken(req.RefreshToken)).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to log out",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Logged out successfully",
+	})
+}
+
+// issueTokenPair signs an access token and stores a new refresh token for a user.
+func issueTokenPair(tx *gorm.DB, user *models.User) (*models.TokenResponse, *models.RefreshToken, error) {
+	accessToken, expiresAt, err := utils.IssueAccessToken(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, hash, err := utils.GenerateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh := models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := tx.Omit("User").Create(&refresh).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
+	}, &refresh, nil
+}
+
+// revokeTokenFamily revokes every refresh token of the user owning the given token.
+func revokeTokenFamily(token string) {
+	var reused models.RefreshToken
+	if err := db.DB.Where("token_hash = ?", utils.HashToken(token)).First(&reused).Error; err != nil {
+		return
+	}
+	_ = utils.RevokeRefreshTokens(db.DB, reused.UserID)
+}
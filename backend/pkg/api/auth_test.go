@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// useTestDB points the handlers at a migrated throwaway schema.
+func useTestDB(t *testing.T) {
+	t.Helper()
+	conn := dbtest.Migrated(t)
+	previous := db.DB
+	db.DB = conn
+	t.Cleanup(func() { db.DB = previous })
+}
+
+// postJSON calls a handler with a JSON body and decodes the token pair it returns.
+func postJSON(t *testing.T, handler gin.HandlerFunc, body interface{}) (int, *models.TokenResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(raw))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler(c)
+
+	var response struct {
+		Data *models.TokenResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid response %s: %v", w.Body.String(), err)
+	}
+	return w.Code, response.Data
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	useTestDB(t)
+
+	user, err := utils.CreateUser(db.DB, "asha", "asha@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, login := postJSON(t, Login, models.LoginRequest{Username: "asha", Password: "correct horse"})
+	if code != http.StatusOK {
+		t.Fatalf("login returned %d", code)
+	}
+	if _, err := utils.ParseAccessToken(login.AccessToken); err != nil {
+		t.Fatalf("login issued an invalid access token: %v", err)
+	}
+
+	code, rotated := postJSON(t, RefreshToken, models.RefreshRequest{RefreshToken: login.RefreshToken})
+	if code != http.StatusOK {
+		t.Fatalf("refresh returned %d", code)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Fatal("refresh returned the presented refresh token")
+	}
+
+	var old models.RefreshToken
+	if err := db.DB.Where("token_hash = ?", utils.HashToken(login.RefreshToken)).First(&old).Error; err != nil {
+		t.Fatal(err)
+	}
+	if old.RevokedAt == nil || old.ReplacedByID == nil {
+		t.Fatal("the rotated refresh token was not revoked and linked to its replacement")
+	}
+
+	// Presenting the rotated token again is treated as a leak and revokes the whole family.
+	if code, _ := postJSON(t, RefreshToken, models.RefreshRequest{RefreshToken: login.RefreshToken}); code != http.StatusUnauthorized {
+		t.Fatalf("reusing a rotated token returned %d, want 401", code)
+	}
+	var active int64
+	if err := db.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", user.ID).
+		Count(&active).Error; err != nil {
+		t.Fatal(err)
+	}
+	if active != 0 {
+		t.Fatalf("%d refresh tokens are still active after reuse", active)
+	}
+	if code, _ := postJSON(t, RefreshToken, models.RefreshRequest{RefreshToken: rotated.RefreshToken}); code != http.StatusUnauthorized {
+		t.Fatalf("the replacement token still works after reuse: %d", code)
+	}
+}
+
+func TestRefreshTokenUnknown(t *testing.T) {
+	useTestDB(t)
+	if code, _ := postJSON(t, RefreshToken, models.RefreshRequest{RefreshToken: "unknown"}); code != http.StatusUnauthorized {
+		t.Fatalf("unknown refresh token returned %d, want 401", code)
+	}
+}
+
+func TestSetRoleRevokesRefreshTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	useTestDB(t)
+
+	user, err := utils.CreateUser(db.DB, "ravi", "ravi@example.org", "correct horse", models.RoleReviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, login := postJSON(t, Login, models.LoginRequest{Username: "ravi", Password: "correct horse"})
+	if code != http.StatusOK {
+		t.Fatalf("login returned %d", code)
+	}
+
+	if err := utils.SetRole(db.DB, user, models.RoleApplicant); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := postJSON(t, RefreshToken, models.RefreshRequest{RefreshToken: login.RefreshToken}); code != http.StatusUnauthorized {
+		t.Fatalf("refresh token issued before the role change returned %d, want 401", code)
+	}
+}
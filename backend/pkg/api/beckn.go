@@ -0,0 +1,445 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/beckn"
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// becknProcessor handles a validated Beckn request and returns the callback message.
+type becknProcessor func(req beckn.Request) (interface{}, *beckn.Error)
+
+// BecknSearch returns the scheme catalog to a seeker platform.
+//
+// @Summary Beckn search
+// @Description Acknowledges the request and sends the catalog of open and upcoming schemes to {bap_uri}/on_search. The intent item name filters schemes by name.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn search request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/search [post]
+func BecknSearch(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionSearch, processBecknSearch)
+}
+
+// BecknSelect returns the details and quote of a scheme.
+//
+// @Summary Beckn select
+// @Description Acknowledges the request and sends the selected scheme with its benefit amount to {bap_uri}/on_select.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn select request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/select [post]
+func BecknSelect(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionSelect, processBecknSelect)
+}
+
+// BecknInit initializes a draft application for the customer of an order.
+//
+// @Summary Beckn init
+// @Description Acknowledges the request, creates or updates the draft application for the customer and scheme, and sends the order to {bap_uri}/on_init.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn init request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/init [post]
+func BecknInit(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionInit, processBecknInit)
+}
+
+// BecknConfirm submits the application of an order.
+//
+// @Summary Beckn confirm
+// @Description Acknowledges the request, submits the application with the same checks as the REST API, and sends the result to {bap_uri}/on_confirm.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn confirm request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/confirm [post]
+func BecknConfirm(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionConfirm, processBecknConfirm)
+}
+
+// BecknStatus reports the status of the application of an order.
+//
+// @Summary Beckn status
+// @Description Acknowledges the request and sends the order with its application status to {bap_uri}/on_status.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn status request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/status [post]
+func BecknStatus(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionStatus, processBecknStatus)
+}
+
+// BecknCancel withdraws the application of an order.
+//
+// @Summary Beckn cancel
+// @Description Acknowledges the request, withdraws the application, and sends the cancelled order to {bap_uri}/on_cancel.
+// @Tags Beckn
+// @Accept json
+// @Produce json
+// @Param request body beckn.Request true "Beckn cancel request"
+// @Success 200 {object} beckn.AckResponse "ACK"
+// @Failure 400 {object} beckn.AckResponse "NACK, invalid request"
+// @Failure 401 {object} beckn.AckResponse "NACK, invalid signature"
+// @Router /beckn/cancel [post]
+func BecknCancel(c *gin.Context) {
+	handleBecknRequest(c, beckn.ActionCancel, processBecknCancel)
+}
+
+// handleBecknRequest checks that the request context matches the action, the verified signer
+// and the callback URL registered for the signer, rejects replayed messages, acknowledges it and
+// processes it in the background, delivering the result to the BAP as an on_* callback.
+func handleBecknRequest(c *gin.Context, action string, process becknProcessor) {
+	var req beckn.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, beckn.NewNack(beckn.ErrCodeInvalidRequest, err.Error()))
+		return
+	}
+
+	ctx := req.Context
+	switch {
+	case ctx.Action != action:
+		c.JSON(http.StatusBadRequest, beckn.NewNack(beckn.ErrCodeInvalidRequest, "context action must be "+action))
+		return
+	case ctx.BapID == "" || ctx.BapURI == "":
+		c.JSON(http.StatusBadRequest, beckn.NewNack(beckn.ErrCodeInvalidRequest, "bap_id and bap_uri are required"))
+		return
+	case ctx.TransactionID == "" || ctx.MessageID == "":
+		c.JSON(http.StatusBadRequest, beckn.NewNack(beckn.ErrCodeInvalidRequest, "transaction_id and message_id are required"))
+		return
+	case ctx.BapID != c.GetString("subscriber_id"):
+		c.JSON(http.StatusUnauthorized, beckn.NewNack(beckn.ErrCodeInvalidSignature, "request is not signed by "+ctx.BapID))
+		return
+	case !beckn.Registry.MatchesSubscriberURL(ctx.BapID, ctx.BapURI):
+		c.JSON(http.StatusUnauthorized, beckn.NewNack(beckn.ErrCodeInvalidRequest, "bap_uri is not the subscriber_url registered for "+ctx.BapID))
+		return
+	case beckn.Replays.Seen(ctx.BapID, ctx.MessageID, c.GetTime("signature_expires"), time.Now()):
+		c.JSON(http.StatusConflict, beckn.NewNack(beckn.ErrCodeInvalidRequest, "message_id "+ctx.MessageID+" was already received"))
+		return
+	}
+
+	c.JSON(http.StatusOK, beckn.NewAck())
+
+	go func() {
+		message, becknErr := process(req)
+		callback := beckn.Callback{
+			Context: beckn.CallbackContext(req.Context),
+			Message: message,
+			Error:   becknErr,
+		}
+		sendCtx, cancel := context.WithTimeout(context.Background(), beckn.CallbackTimeout)
+		defer cancel()
+		if err := beckn.SendCallback(sendCtx, callback); err != nil {
+			log.Printf("beckn: %v", err)
+		}
+	}()
+}
+
+func processBecknSearch(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.SearchMessage
+	if len(req.Message) > 0 {
+		if err := json.Unmarshal(req.Message, &msg); err != nil {
+			return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+		}
+	}
+
+	query := db.DB.
+		Preload("Eligibility.DocumentMappings.Document").
+		Where("status <> ?", models.SchemeStatusClosed)
+	if item := msg.Intent.Item; item != nil {
+		if item.ID != "" {
+			query = query.Where("id = ?", item.ID)
+		}
+		if item.Descriptor != nil && item.Descriptor.Name != "" {
+			query = query.Where("name ILIKE ?", "%"+item.Descriptor.Name+"%")
+		}
+	}
+
+	var schemes []models.Scheme
+	if err := query.Order("end_date ASC").Find(&schemes).Error; err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to fetch schemes"}
+	}
+
+	items := make([]beckn.Item, 0, len(schemes))
+	for i := range schemes {
+		items = append(items, beckn.SchemeItem(&schemes[i]))
+	}
+	return beckn.CatalogMessage{
+		Catalog: beckn.Catalog{
+			Descriptor: beckn.Descriptor{Name: "Beneficiary Manager"},
+			Providers: []beckn.Provider{{
+				ID:         beckn.BPP.ID,
+				Descriptor: &beckn.Descriptor{Name: "Beneficiary Manager"},
+				Items:      items,
+			}},
+		},
+	}, nil
+}
+
+func processBecknSelect(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.OrderMessage
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	scheme, becknErr := findBecknScheme(msg.Order)
+	if becknErr != nil {
+		return nil, becknErr
+	}
+
+	item := beckn.SchemeItem(scheme)
+	return beckn.OrderMessage{
+		Order: beckn.Order{
+			Provider: &beckn.Provider{ID: beckn.BPP.ID},
+			Items:    []beckn.Item{item},
+			Quote:    &beckn.Quotation{Price: *item.Price},
+		},
+	}, nil
+}
+
+func processBecknInit(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.OrderMessage
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	scheme, becknErr := findBecknScheme(msg.Order)
+	if becknErr != nil {
+		return nil, becknErr
+	}
+	if len(msg.Order.Fulfillments) == 0 || msg.Order.Fulfillments[0].Customer == nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: "order fulfillment must include the customer"}
+	}
+	customer := msg.Order.Fulfillments[0].Customer
+	identity := beckn.CustomerIdentity(customer)
+	if identity == "" {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: "customer contact email or phone is required"}
+	}
+	profile, documents, err := beckn.CustomerProfile(customer)
+	if err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+	if err := utils.CheckSchemeOpen(scheme, time.Now()); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeItemUnavailable, Message: err.Error()}
+	}
+
+	var application *models.Application
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := becknApplicant(tx, req.Context.BapID, identity)
+		if err != nil {
+			return err
+		}
+
+		application, err = utils.InitApplication(tx, user.ID, scheme.ID, profile)
+		if errors.Is(err, utils.ErrApplicationExists) {
+			// A repeated init updates the profile of the existing draft
+			application = &models.Application{}
+			if err := tx.Where("user_id = ? AND scheme_id = ?", user.ID, scheme.ID).First(application).Error; err != nil {
+				return err
+			}
+			if !application.Status.IsEditable() && application.Status != "" {
+				return models.ErrIllegalTransition
+			}
+			err = utils.OverwriteStudentProfileFields(tx, application.StudentProfileID, profile)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := utils.UpsertStudentDocuments(tx, application.StudentProfileID, documents); err != nil {
+			return err
+		}
+
+		order := models.BecknOrder{
+			ApplicationID: application.ID,
+			BapID:         req.Context.BapID,
+			TransactionID: req.Context.TransactionID,
+		}
+		return tx.Where(models.BecknOrder{ApplicationID: application.ID}).FirstOrCreate(&order).Error
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrIllegalTransition) {
+			return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: "application has already been submitted"}
+		}
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to initialize application"}
+	}
+
+	if err := utils.PreloadApplicationDetails(db.DB).First(application, application.ID).Error; err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to load application"}
+	}
+	return beckn.OrderMessage{Order: beckn.ApplicationOrder(application)}, nil
+}
+
+func processBecknConfirm(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.OrderMessage
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	application, becknErr := findBecknApplication(req.Context.BapID, msg.Order.ID)
+	if becknErr != nil {
+		return nil, becknErr
+	}
+
+	if err := utils.SubmitApplication(db.DB, application, application.UserID); err != nil {
+		var submissionErr *utils.SubmissionError
+		switch {
+		case errors.As(err, &submissionErr):
+			return nil, &beckn.Error{Code: beckn.ErrCodeNotEligible, Message: submissionErr.Message + ": " + submissionErr.Error()}
+		case errors.Is(err, models.ErrIllegalTransition):
+			return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+		case errors.Is(err, utils.ErrSchemeNotOpen):
+			return nil, &beckn.Error{Code: beckn.ErrCodeItemUnavailable, Message: err.Error()}
+		default:
+			return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to submit application"}
+		}
+	}
+	return beckn.OrderMessage{Order: beckn.ApplicationOrder(application)}, nil
+}
+
+func processBecknStatus(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.StatusMessage
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	application, becknErr := findBecknApplication(req.Context.BapID, msg.OrderID)
+	if becknErr != nil {
+		return nil, becknErr
+	}
+	return beckn.OrderMessage{Order: beckn.ApplicationOrder(application)}, nil
+}
+
+func processBecknCancel(req beckn.Request) (interface{}, *beckn.Error) {
+	var msg beckn.CancelMessage
+	if err := json.Unmarshal(req.Message, &msg); err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: err.Error()}
+	}
+
+	application, becknErr := findBecknApplication(req.Context.BapID, msg.OrderID)
+	if becknErr != nil {
+		return nil, becknErr
+	}
+
+	reason := msg.CancellationReasonID
+	if msg.Descriptor != nil && msg.Descriptor.ShortDesc != "" {
+		reason = msg.Descriptor.ShortDesc
+	}
+	err := utils.TransitionApplication(db.DB, application, models.ApplicationStatusWithdrawn,
+		models.PermissionApplicationOwn, application.UserID, reason)
+	if err != nil {
+		if errors.Is(err, models.ErrIllegalTransition) {
+			return nil, &beckn.Error{Code: beckn.ErrCodeNotCancellable, Message: err.Error()}
+		}
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to cancel application"}
+	}
+	return beckn.OrderMessage{Order: beckn.ApplicationOrder(application)}, nil
+}
+
+// findBecknScheme loads the non-closed scheme referenced by the first item of an order.
+func findBecknScheme(order beckn.Order) (*models.Scheme, *beckn.Error) {
+	if len(order.Items) == 0 {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInvalidRequest, Message: "order must include an item"}
+	}
+	id, err := beckn.ParseID(order.Items[0].ID)
+	if err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeItemNotFound, Message: err.Error()}
+	}
+
+	var scheme models.Scheme
+	if err := db.DB.
+		Preload("Eligibility.DocumentMappings.Document").
+		Where("status <> ?", models.SchemeStatusClosed).
+		First(&scheme, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &beckn.Error{Code: beckn.ErrCodeItemNotFound, Message: "scheme not found"}
+		}
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to fetch scheme"}
+	}
+	return &scheme, nil
+}
+
+// findBecknApplication loads the application of an order created by the given BAP.
+func findBecknApplication(bapID, orderID string) (*models.Application, *beckn.Error) {
+	id, err := beckn.ParseID(orderID)
+	if err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeOrderNotFound, Message: err.Error()}
+	}
+
+	var order models.BecknOrder
+	if err := db.DB.Where("application_id = ? AND bap_id = ?", id, bapID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &beckn.Error{Code: beckn.ErrCodeOrderNotFound, Message: "order not found"}
+		}
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to fetch order"}
+	}
+
+	var application models.Application
+	if err := utils.PreloadApplicationDetails(db.DB).First(&application, order.ApplicationID).Error; err != nil {
+		return nil, &beckn.Error{Code: beckn.ErrCodeInternal, Message: "failed to fetch application"}
+	}
+	return &application, nil
+}
+
+// becknApplicant finds or creates the shadow applicant account of a BAP customer.
+// The account gets a random password so it can only be used through the BAP.
+func becknApplicant(tx *gorm.DB, bapID, identity string) (*models.User, error) {
+	username := beckn.ApplicantUsername(bapID, identity)
+
+	var user models.User
+	err := tx.Where("username = ?", username).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	secret, _, err := utils.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := utils.HashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+	user = models.User{
+		Username: username,
+		Password: hash,
+		Role:     models.RoleApplicant,
+		IsActive: true,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
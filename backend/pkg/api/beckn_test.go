@@ -0,0 +1,343 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/beckn"
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/middleware"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testBapID  = "bap.example.org"
+	testBapURI = "https://bap.example.org/beckn"
+)
+
+// becknRequest builds a request of the test BAP with a fresh message ID.
+func becknRequest(t *testing.T, action string, message interface{}) beckn.Request {
+	t.Helper()
+	raw, err := json.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return beckn.Request{
+		Context: beckn.Context{
+			Domain:        beckn.Domain,
+			Action:        action,
+			Version:       beckn.Version,
+			BapID:         testBapID,
+			BapURI:        testBapURI,
+			TransactionID: "txn-1",
+			MessageID:     "msg-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+			Timestamp:     time.Now().UTC(),
+		},
+		Message: raw,
+	}
+}
+
+// useTestRegistry registers a signing key for the test BAP and returns it. The replay cache is
+// reset for the test.
+func useTestRegistry(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := beckn.NewKeyRegistry([]beckn.RegistryEntry{
+		{SubscriberID: testBapID, SubscriberURL: testBapURI, UniqueKeyID: "key-1", SigningPublicKey: base64.StdEncoding.EncodeToString(public)},
+		{SubscriberID: "other.example.org", SubscriberURL: "https://other.example.org/beckn", UniqueKeyID: "key-1", SigningPublicKey: base64.StdEncoding.EncodeToString(public)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousRegistry, previousReplays := beckn.Registry, beckn.Replays
+	beckn.Registry, beckn.Replays = registry, beckn.NewReplayCache()
+	t.Cleanup(func() { beckn.Registry, beckn.Replays = previousRegistry, previousReplays })
+	return private
+}
+
+func TestHandleBecknRequestChecksContext(t *testing.T) {
+	key := useTestRegistry(t)
+	processed := make(chan beckn.Request, 10)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/search", middleware.BecknSignatureAuth(), func(c *gin.Context) {
+		handleBecknRequest(c, beckn.ActionSearch, func(req beckn.Request) (interface{}, *beckn.Error) {
+			processed <- req
+			return nil, nil
+		})
+	})
+	send := func(signer string, req beckn.Request) (int, beckn.AckResponse) {
+		t.Helper()
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpReq := httptest.NewRequest(http.MethodPost, "/search", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", beckn.Sign(body, signer, "key-1", key, time.Now()).String())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		var ack beckn.AckResponse
+		json.Unmarshal(w.Body.Bytes(), &ack)
+		return w.Code, ack
+	}
+
+	valid := becknRequest(t, beckn.ActionSearch, beckn.SearchMessage{})
+	wrongAction := becknRequest(t, beckn.ActionSelect, beckn.SearchMessage{})
+	noBap := becknRequest(t, beckn.ActionSearch, beckn.SearchMessage{})
+	noBap.Context.BapURI = ""
+	noMessageID := becknRequest(t, beckn.ActionSearch, beckn.SearchMessage{})
+	noMessageID.Context.MessageID = ""
+	otherURI := becknRequest(t, beckn.ActionSearch, beckn.SearchMessage{})
+	otherURI.Context.BapURI = "https://attacker.example.org/beckn"
+
+	tests := []struct {
+		name   string
+		signer string
+		req    beckn.Request
+		want   int
+	}{
+		{"wrong action", testBapID, wrongAction, http.StatusBadRequest},
+		{"missing bap_uri", testBapID, noBap, http.StatusBadRequest},
+		{"missing message_id", testBapID, noMessageID, http.StatusBadRequest},
+		{"signed by another subscriber", "other.example.org", valid, http.StatusUnauthorized},
+		{"unregistered bap_uri", testBapID, otherURI, http.StatusUnauthorized},
+		{"valid", testBapID, valid, http.StatusOK},
+		{"replayed", testBapID, valid, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		code, ack := send(tt.signer, tt.req)
+		if code != tt.want {
+			t.Errorf("%s returned %d with %+v, want %d", tt.name, code, ack, tt.want)
+		}
+		if wantAck := tt.want == http.StatusOK; (ack.Message.Ack.Status == "ACK") != wantAck {
+			t.Errorf("%s answered %q", tt.name, ack.Message.Ack.Status)
+		}
+	}
+
+	select {
+	case req := <-processed:
+		if req.Context.MessageID != valid.Context.MessageID {
+			t.Errorf("processed message %q, want %q", req.Context.MessageID, valid.Context.MessageID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the acknowledged request was not processed")
+	}
+	if len(processed) != 0 {
+		t.Errorf("%d refused requests were processed", len(processed))
+	}
+}
+
+// createBecknSchemes stores an open scheme requiring an Aadhaar card, an upcoming scheme and a
+// closed scheme.
+func createBecknSchemes(t *testing.T) (open, upcoming, closed *models.Scheme) {
+	t.Helper()
+	now := time.Now()
+	create := func(name, status string, start, end time.Time) *models.Scheme {
+		scheme, err := utils.CreateScheme(db.DB, models.SchemeInput{
+			Name:      name,
+			Amount:    5000,
+			StartDate: start,
+			EndDate:   end,
+			Status:    status,
+			Eligibility: models.EligibilityInput{
+				Category:  models.CategoryGeneral,
+				Documents: []models.EligibilityDocumentInput{{Name: "aadhar_card", IsMandatory: true}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scheme
+	}
+	open = create("Merit scholarship", models.SchemeStatusOpen, now.AddDate(0, -1, 0), now.AddDate(1, 0, 0))
+	upcoming = create("Sports scholarship", models.SchemeStatusUpcoming, now.AddDate(0, 1, 0), now.AddDate(2, 0, 0))
+	closed = create("Merit scholarship 2020", models.SchemeStatusClosed, now.AddDate(-5, 0, 0), now.AddDate(-4, 0, 0))
+	return open, upcoming, closed
+}
+
+// itemIDs returns the item IDs of the first provider of a search result.
+func itemIDs(t *testing.T, message interface{}) []string {
+	t.Helper()
+	catalog, ok := message.(beckn.CatalogMessage)
+	if !ok || len(catalog.Catalog.Providers) != 1 {
+		t.Fatalf("search returned %#v, want a catalog with one provider", message)
+	}
+	var ids []string
+	for _, item := range catalog.Catalog.Providers[0].Items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestBecknSearchAndSelect(t *testing.T) {
+	useTestDB(t)
+	open, upcoming, closed := createBecknSchemes(t)
+	openID := strconv.FormatUint(uint64(open.ID), 10)
+
+	message, becknErr := processBecknSearch(becknRequest(t, beckn.ActionSearch, beckn.SearchMessage{}))
+	if becknErr != nil {
+		t.Fatalf("search failed: %+v", becknErr)
+	}
+	ids := itemIDs(t, message)
+	if len(ids) != 2 || ids[0] != openID || ids[1] != strconv.FormatUint(uint64(upcoming.ID), 10) {
+		t.Errorf("catalog = %v, want the open and upcoming schemes", ids)
+	}
+
+	byName := beckn.SearchMessage{Intent: beckn.Intent{Item: &beckn.Item{Descriptor: &beckn.Descriptor{Name: "merit"}}}}
+	message, becknErr = processBecknSearch(becknRequest(t, beckn.ActionSearch, byName))
+	if becknErr != nil {
+		t.Fatalf("search by name failed: %+v", becknErr)
+	}
+	if ids := itemIDs(t, message); len(ids) != 1 || ids[0] != openID {
+		t.Errorf("search for merit = %v, want only the open scheme", ids)
+	}
+
+	order := func(id string) beckn.OrderMessage {
+		return beckn.OrderMessage{Order: beckn.Order{Items: []beckn.Item{{ID: id}}}}
+	}
+	message, becknErr = processBecknSelect(becknRequest(t, beckn.ActionSelect, order(openID)))
+	if becknErr != nil {
+		t.Fatalf("select failed: %+v", becknErr)
+	}
+	selected := message.(beckn.OrderMessage).Order
+	if len(selected.Items) != 1 || selected.Items[0].ID != openID || selected.Quote == nil || selected.Quote.Price.Value != "5000.00" {
+		t.Errorf("select = %+v, want the open scheme quoted at 5000.00", selected)
+	}
+
+	tests := []struct {
+		name string
+		msg  beckn.OrderMessage
+		want string
+	}{
+		{"closed scheme", order(strconv.FormatUint(uint64(closed.ID), 10)), beckn.ErrCodeItemNotFound},
+		{"invalid id", order("1 OR 1=1"), beckn.ErrCodeItemNotFound},
+		{"no item", beckn.OrderMessage{}, beckn.ErrCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		if _, becknErr := processBecknSelect(becknRequest(t, beckn.ActionSelect, tt.msg)); becknErr == nil || becknErr.Code != tt.want {
+			t.Errorf("select of %s = %+v, want code %s", tt.name, becknErr, tt.want)
+		}
+	}
+}
+
+func TestBecknInit(t *testing.T) {
+	useTestDB(t)
+	open, upcoming, _ := createBecknSchemes(t)
+
+	customer := &beckn.Customer{
+		Person: beckn.Person{
+			Name:   "Asha Rao",
+			Gender: "Female",
+			Dob:    "2004-05-17",
+			Creds:  []beckn.Credential{{Type: "aadhar_card", URL: "https://files.example.org/aadhar.pdf"}},
+			Tags: []beckn.TagGroup{{List: []beckn.Tag{
+				{Descriptor: beckn.Descriptor{Code: beckn.TagCategory}, Value: string(models.CategoryGeneral)},
+				{Descriptor: beckn.Descriptor{Code: beckn.TagIncome}, Value: "120000"},
+			}}},
+		},
+		Contact: beckn.Contact{Email: "Asha@Example.org"},
+	}
+	initOrder := func(schemeID uint, customer *beckn.Customer) beckn.OrderMessage {
+		order := beckn.Order{Items: []beckn.Item{{ID: strconv.FormatUint(uint64(schemeID), 10)}}}
+		if customer != nil {
+			order.Fulfillments = []beckn.Fulfillment{{Customer: customer}}
+		}
+		return beckn.OrderMessage{Order: order}
+	}
+
+	message, becknErr := processBecknInit(becknRequest(t, beckn.ActionInit, initOrder(open.ID, customer)))
+	if becknErr != nil {
+		t.Fatalf("init failed: %+v", becknErr)
+	}
+	order := message.(beckn.OrderMessage).Order
+	if order.Status != "CREATED" || order.Fulfillments[0].State.Descriptor.Code != string(models.ApplicationStatusDraft) {
+		t.Errorf("init order = %+v, want a created draft", order)
+	}
+
+	var user models.User
+	if err := db.DB.Where("username = ?", beckn.ApplicantUsername(testBapID, "asha@example.org")).First(&user).Error; err != nil {
+		t.Fatalf("shadow applicant not created: %v", err)
+	}
+	var application models.Application
+	if err := db.DB.Preload("StudentProfile.Documents").Where("user_id = ? AND scheme_id = ?", user.ID, open.ID).First(&application).Error; err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != strconv.FormatUint(uint64(application.ID), 10) {
+		t.Errorf("order ID = %s, want application %d", order.ID, application.ID)
+	}
+	profile := application.StudentProfile
+	if profile.FullName != "Asha Rao" || profile.Income != 120000 || len(profile.Documents) != 1 || profile.Documents[0].Name != "aadhar_card" {
+		t.Errorf("profile = %+v, want the customer details and Aadhaar card", profile)
+	}
+	var orders int64
+	db.DB.Model(&models.BecknOrder{}).Where("application_id = ? AND bap_id = ?", application.ID, testBapID).Count(&orders)
+	if orders != 1 {
+		t.Errorf("%d orders recorded for the application, want 1", orders)
+	}
+
+	// A repeated init updates the existing draft instead of starting another application
+	renamed := *customer
+	renamed.Person.Name = "Asha R."
+	message, becknErr = processBecknInit(becknRequest(t, beckn.ActionInit, initOrder(open.ID, &renamed)))
+	if becknErr != nil {
+		t.Fatalf("repeated init failed: %+v", becknErr)
+	}
+	if id := message.(beckn.OrderMessage).Order.ID; id != order.ID {
+		t.Errorf("repeated init returned order %s, want %s", id, order.ID)
+	}
+	var updated models.StudentProfile
+	if err := db.DB.First(&updated, application.StudentProfileID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if updated.FullName != "Asha R." {
+		t.Errorf("repeated init kept the name %q", updated.FullName)
+	}
+
+	badDob := *customer
+	badDob.Person.Dob = "17/05/2004"
+	noContact := *customer
+	noContact.Contact = beckn.Contact{}
+	tests := []struct {
+		name string
+		msg  beckn.OrderMessage
+		want string
+	}{
+		{"no customer", initOrder(open.ID, nil), beckn.ErrCodeInvalidRequest},
+		{"no contact", initOrder(open.ID, &noContact), beckn.ErrCodeInvalidRequest},
+		{"invalid dob", initOrder(open.ID, &badDob), beckn.ErrCodeInvalidRequest},
+		{"upcoming scheme", initOrder(upcoming.ID, customer), beckn.ErrCodeItemUnavailable},
+	}
+	for _, tt := range tests {
+		if _, becknErr := processBecknInit(becknRequest(t, beckn.ActionInit, tt.msg)); becknErr == nil || becknErr.Code != tt.want {
+			t.Errorf("init with %s = %+v, want code %s", tt.name, becknErr, tt.want)
+		}
+	}
+
+	// Submitted applications can no longer be changed through init
+	if err := db.DB.Model(&application).Updates(map[string]interface{}{"status": models.ApplicationStatusSubmitted, "is_draft": false}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, becknErr := processBecknInit(becknRequest(t, beckn.ActionInit, initOrder(open.ID, customer))); becknErr == nil || becknErr.Code != beckn.ErrCodeInvalidRequest {
+		t.Errorf("init of a submitted application = %+v, want code %s", becknErr, beckn.ErrCodeInvalidRequest)
+	}
+
+	status := beckn.StatusMessage{OrderID: order.ID}
+	other := becknRequest(t, beckn.ActionStatus, status)
+	other.Context.BapID = "other.example.org"
+	if _, becknErr := processBecknStatus(other); becknErr == nil || becknErr.Code != beckn.ErrCodeOrderNotFound {
+		t.Errorf("status of another BAP's order = %+v, want code %s", becknErr, beckn.ErrCodeOrderNotFound)
+	}
+}
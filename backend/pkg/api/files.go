@@ -0,0 +1,329 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UploadDocumentFile stores an uploaded document file and attaches it to an application.
+//
+// @Summary Upload document file
+// @Description Stores a PDF, JPEG or PNG file of up to 5 MB and attaches it to the application under the given document name, replacing any earlier file with that name. The document URL is a download link signed whenever the document is returned. After submission only rejected documents can be replaced, and only until the application is decided.
+// @Tags Applications
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param name formData string true "Document name, e.g. aadhar_card"
+// @Param file formData file true "Document file"
+// @Success 201 {object} models.SuccessResponse{data=models.UploadDocument} "Document uploaded successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized, user ID not found in context"
+// @Failure 403 {object} models.ErrorResponse "Document cannot be replaced in the current state"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application no longer accepts documents"
+// @Failure 413 {object} models.ErrorResponse "File is too large"
+// @Failure 415 {object} models.ErrorResponse "Unsupported file type"
+// @Failure 500 {object} models.ErrorResponse "Failed to upload document"
+// @Router /applications/{id}/documents/upload [post]
+func UploadDocumentFile(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var application models.Application
+	if err := db.DB.
+		Where("id = ? AND user_id = ?", c.Param("id"), userID).
+		First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Application not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, utils.MaxUploadSize+1<<20)
+	name := c.PostForm("name")
+	fileHeader, err := c.FormFile("file")
+	if err != nil || name == "" {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondFileTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "A document name and a file are required",
+			Error:   fmt.Sprint(err),
+		})
+		return
+	}
+	if fileHeader.Size > utils.MaxUploadSize {
+		respondFileTooLarge(c)
+		return
+	}
+
+	var known int64
+	if err := db.DB.Model(&models.DocumentsRequired{}).Where("name = ?", name).Count(&known).Error; err != nil || known == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Unknown document name",
+			Error:   "unknown document " + name,
+		})
+		return
+	}
+
+	var document models.UploadDocument
+	err = db.DB.Where("student_id = ? AND name = ?", application.StudentProfileID, name).First(&document).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to upload document",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !application.IsDraft && !application.Status.AcceptsDocuments() {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Documents can no longer be changed",
+			Error:   "application is " + string(application.Status),
+		})
+		return
+	}
+	if !application.IsDraft && (document.ID == 0 || document.VerificationStatus != models.DocumentRejected) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Only rejected documents can be replaced after submission",
+			Error:   "application is " + string(application.Status),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to read uploaded file",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to read uploaded file",
+			Error:   err.Error(),
+		})
+		return
+	}
+	head = head[:n]
+	contentType, err := utils.DetectUploadType(head)
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Only PDF, JPEG and PNG files are accepted",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	random, _, err := utils.GenerateToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to upload document",
+			Error:   err.Error(),
+		})
+		return
+	}
+	key := fmt.Sprintf("applications/%d/%s-%s%s", application.ID, name, random[:16], utils.AllowedUploadTypes[contentType])
+
+	hasher := sha256.New()
+	body := io.TeeReader(io.MultiReader(bytes.NewReader(head), file), hasher)
+	if err := storage.Store.Put(c.Request.Context(), key, body, fileHeader.Size, contentType); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to store document",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	previousKey := document.StorageKey
+	document.StudentID = application.StudentProfileID
+	document.Name = name
+	document.URL = storage.FilePath(key)
+	document.StorageKey = key
+	document.ContentType = contentType
+	document.Size = fileHeader.Size
+	document.Checksum = hex.EncodeToString(hasher.Sum(nil))
+	document.VerificationStatus = models.DocumentPending
+	document.RejectionReason = ""
+	document.VerifiedByID = nil
+	document.VerifiedAt = nil
+	document.UpdatedAt = time.Now()
+
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("StudentProfile").Save(&document).Error; err != nil {
+			return err
+		}
+		return utils.RefreshApplicationVerified(tx, &application)
+	}); err != nil {
+		discardObject(c, key)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to upload document",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if previousKey != "" {
+		discardObject(c, previousKey)
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Document uploaded successfully",
+		Data:    document,
+	})
+}
+
+// DownloadFile streams a stored document file to holders of a valid signed link.
+//
+// @Summary Download document file
+// @Description Serves a stored document. The expires and signature parameters come from the signed link in the document URL.
+// @Tags Files
+// @Produce octet-stream
+// @Param key path string true "Storage key"
+// @Param expires query int true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file "Document file"
+// @Failure 403 {object} models.ErrorResponse "Invalid or expired link"
+// @Failure 404 {object} models.ErrorResponse "File not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to read file"
+// @Router /files/{key} [get]
+func DownloadFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := storage.VerifyLink(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Invalid or expired link",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Replaced files stay available while a submission snapshot still lists them
+	document, err := findStoredDocument(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "File not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to read file",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	reader, err := storage.Store.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "File not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to read file",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer reader.Close()
+
+	c.Header("X-Checksum-SHA256", document.Checksum)
+	c.DataFromReader(http.StatusOK, document.Size, document.ContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", document.Name+utils.AllowedUploadTypes[document.ContentType]),
+	})
+}
+
+func respondFileTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: fmt.Sprintf("File is larger than %d MB", utils.MaxUploadSize>>20),
+		Error:   "file too large",
+	})
+}
+
+// findStoredDocument returns the document stored under key, falling back to the copy
+// kept in a submission snapshot once the document itself was replaced or removed.
+func findStoredDocument(key string) (*models.UploadDocument, error) {
+	var document models.UploadDocument
+	err := db.DB.Where("storage_key = ?", key).First(&document).Error
+	if err == nil {
+		return &document, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	snapshot, err := utils.FindSnapshotDocument(db.DB, key)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UploadDocument{
+		Name:        snapshot.Name,
+		StorageKey:  snapshot.StorageKey,
+		ContentType: snapshot.ContentType,
+		Size:        snapshot.Size,
+		Checksum:    snapshot.Checksum,
+	}, nil
+}
+
+// discardObject removes a stored object that is no longer referenced, logging failures.
+// Objects still used by another document or listed in a submission snapshot are kept.
+func discardObject(c *gin.Context, key string) {
+	if _, err := findStoredDocument(key); !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err != nil {
+			log.Printf("failed to check references of stored object %s: %v", key, err)
+		}
+		return
+	}
+	if err := storage.Store.Delete(c.Request.Context(), key); err != nil {
+		log.Printf("failed to delete stored object %s: %v", key, err)
+	}
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// createDraftApplication stores an applicant with a draft application and returns both.
+func createDraftApplication(t *testing.T) (*models.User, *models.Application) {
+	t.Helper()
+	user, err := utils.CreateUser(db.DB, "applicant", "applicant@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheme := models.Scheme{Name: "Merit scholarship", Status: "open", Eligibility: models.Eligibility{Category: "General"}}
+	if err := db.DB.Create(&scheme).Error; err != nil {
+		t.Fatal(err)
+	}
+	profile := models.StudentProfile{UserID: user.ID, FullName: "Asha Rao"}
+	if err := db.DB.Create(&profile).Error; err != nil {
+		t.Fatal(err)
+	}
+	application := models.Application{
+		UserID:           user.ID,
+		SchemeID:         scheme.ID,
+		StudentProfileID: profile.ID,
+		IsDraft:          true,
+		Status:           models.ApplicationStatusDraft,
+	}
+	if err := db.DB.Omit("User", "Scheme", "StudentProfile").Create(&application).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user, &application
+}
+
+// useTestStorage points the handlers at local storage in a temporary directory.
+func useTestStorage(t *testing.T) {
+	t.Helper()
+	t.Setenv("STORAGE_SIGNING_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("PUBLIC_BASE_URL", "")
+	s, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := storage.Store
+	storage.Store = s
+	t.Cleanup(func() { storage.Store = previous })
+}
+
+// uploadFile posts a multipart upload as the given user and decodes the stored document.
+func uploadFile(t *testing.T, userID, applicationID uint, name string, content []byte) (int, models.UploadDocument) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("name", name)
+	part, err := form.CreateFormFile("file", "upload.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/applications/:id/documents/upload", func(c *gin.Context) {
+		c.Set("user_id", userID)
+	}, UploadDocumentFile)
+	req := httptest.NewRequest(http.MethodPost, "/applications/"+strconv.FormatUint(uint64(applicationID), 10)+"/documents/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.UploadDocument `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response.Data
+}
+
+// download fetches a link through the DownloadFile handler.
+func download(t *testing.T, link string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/files/*key", DownloadFile)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, link, nil))
+	return w
+}
+
+func TestUploadAndDownloadDocument(t *testing.T) {
+	useTestDB(t)
+	useTestStorage(t)
+	user, application := createDraftApplication(t)
+
+	content := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("x"), 1024)...)
+	code, document := uploadFile(t, user.ID, application.ID, "aadhar_card", content)
+	if code != http.StatusCreated {
+		t.Fatalf("upload returned %d", code)
+	}
+	sum := sha256.Sum256(content)
+	if document.Checksum != hex.EncodeToString(sum[:]) || document.ContentType != "application/pdf" || document.Size != int64(len(content)) {
+		t.Errorf("document = %+v", document)
+	}
+
+	var stored models.UploadDocument
+	if err := db.DB.First(&stored, document.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.URL != storage.FilePath(stored.StorageKey) {
+		t.Errorf("stored URL = %q, want the unsigned path", stored.URL)
+	}
+	if !strings.Contains(document.URL, "signature=") {
+		t.Fatalf("response URL %q is not signed", document.URL)
+	}
+
+	w := download(t, document.URL)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), content) {
+		t.Fatalf("download returned %d with %d bytes", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("X-Checksum-SHA256") != document.Checksum {
+		t.Error("download does not carry the checksum")
+	}
+
+	u, _ := url.Parse(document.URL)
+	query := u.Query()
+	query.Set("signature", strings.Repeat("0", 64))
+	u.RawQuery = query.Encode()
+	if w := download(t, u.String()); w.Code != http.StatusForbidden {
+		t.Errorf("tampered link returned %d, want 403", w.Code)
+	}
+	if w := download(t, stored.URL); w.Code != http.StatusForbidden {
+		t.Errorf("unsigned path returned %d, want 403", w.Code)
+	}
+
+	// A replacement removes the old file and the links to it stop working
+	code, replaced := uploadFile(t, user.ID, application.ID, "aadhar_card", []byte("\x89PNG\r\n\x1a\nimage"))
+	if code != http.StatusCreated || replaced.ID != document.ID {
+		t.Fatalf("replacement returned %d for document %d", code, replaced.ID)
+	}
+	if w := download(t, document.URL); w.Code != http.StatusNotFound {
+		t.Errorf("link to the replaced file returned %d, want 404", w.Code)
+	}
+	if _, err := storage.Store.Get(context.Background(), stored.StorageKey); err == nil {
+		t.Error("the replaced file was not deleted")
+	}
+}
+
+func TestUploadRejectsUnsupportedFiles(t *testing.T) {
+	useTestDB(t)
+	useTestStorage(t)
+	user, application := createDraftApplication(t)
+
+	if code, _ := uploadFile(t, user.ID, application.ID, "aadhar_card", []byte("#!/bin/sh\necho hi\n")); code != http.StatusUnsupportedMediaType {
+		t.Errorf("script upload returned %d, want 415", code)
+	}
+	if code, _ := uploadFile(t, user.ID, application.ID, "unknown_document", []byte("%PDF-1.4\n")); code != http.StatusBadRequest {
+		t.Errorf("unknown document name returned %d, want 400", code)
+	}
+	if code, _ := uploadFile(t, user.ID+1, application.ID, "aadhar_card", []byte("%PDF-1.4\n")); code != http.StatusNotFound {
+		t.Errorf("upload to another user's application returned %d, want 404", code)
+	}
+}
+
+func TestReplaceDocumentAfterSubmission(t *testing.T) {
+	useTestDB(t)
+	useTestStorage(t)
+	user, application := createDraftApplication(t)
+
+	code, document := uploadFile(t, user.ID, application.ID, "aadhar_card", []byte("%PDF-1.4\nfirst"))
+	if code != http.StatusCreated {
+		t.Fatalf("draft upload returned %d", code)
+	}
+	setState := func(status models.ApplicationStatus, verification models.DocumentVerificationStatus) {
+		t.Helper()
+		if err := db.DB.Model(application).Updates(map[string]interface{}{"status": status, "is_draft": false}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.DB.Model(&models.UploadDocument{}).Where("id = ?", document.ID).Update("verification_status", verification).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		status       models.ApplicationStatus
+		verification models.DocumentVerificationStatus
+		document     string
+		want         int
+	}{
+		{"pending document under review", models.ApplicationStatusUnderReview, models.DocumentPending, "aadhar_card", http.StatusForbidden},
+		{"new document under review", models.ApplicationStatusUnderReview, models.DocumentRejected, "pan_card", http.StatusForbidden},
+		{"rejected document under review", models.ApplicationStatusUnderReview, models.DocumentRejected, "aadhar_card", http.StatusCreated},
+		{"rejected document while submitted", models.ApplicationStatusSubmitted, models.DocumentRejected, "aadhar_card", http.StatusCreated},
+		{"rejected document after approval", models.ApplicationStatusApproved, models.DocumentRejected, "aadhar_card", http.StatusConflict},
+		{"rejected document after rejection", models.ApplicationStatusRejected, models.DocumentRejected, "aadhar_card", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setState(tt.status, tt.verification)
+			if code, _ := uploadFile(t, user.ID, application.ID, tt.document, []byte("%PDF-1.4\nreplacement")); code != tt.want {
+				t.Errorf("upload returned %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplacedFileStaysWithSnapshot(t *testing.T) {
+	useTestDB(t)
+	useTestStorage(t)
+	user, application := createDraftApplication(t)
+
+	content := []byte("%PDF-1.4\nsubmitted")
+	code, document := uploadFile(t, user.ID, application.ID, "aadhar_card", content)
+	if code != http.StatusCreated {
+		t.Fatalf("upload returned %d", code)
+	}
+	var stored models.UploadDocument
+	if err := db.DB.First(&stored, document.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := utils.CreateApplicationSnapshot(db.DB, application, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The submitted file is kept and served after the applicant replaces it
+	if code, _ := uploadFile(t, user.ID, application.ID, "aadhar_card", []byte("\x89PNG\r\n\x1a\nimage")); code != http.StatusCreated {
+		t.Fatalf("replacement returned %d", code)
+	}
+	if _, err := storage.Store.Get(context.Background(), stored.StorageKey); err != nil {
+		t.Fatalf("the file listed in the snapshot was deleted: %v", err)
+	}
+	link, err := storage.SignURL(stored.StorageKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := download(t, link)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), content) {
+		t.Fatalf("snapshot link returned %d with %d bytes", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("X-Checksum-SHA256") != stored.Checksum || w.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("snapshot download headers = %v", w.Header())
+	}
+}
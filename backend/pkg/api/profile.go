@@ -0,0 +1,120 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetMyProfile returns the saved applicant profile of the authenticated user.
+//
+// @Summary Get saved profile
+// @Description Returns the applicant profile that draft applications use, including documents, addresses and education history.
+// @Description An empty profile is returned until the profile is first saved.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} models.SuccessResponse{data=models.StudentProfile} "Profile fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch profile"
+// @Router /users/me/profile [get]
+func GetMyProfile(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	profile, err := utils.FindApplicantProfile(db.DB, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		profile, err = &models.StudentProfile{UserID: userID, IsPrimary: true}, nil
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Profile fetched successfully",
+		Data:    profile,
+	})
+}
+
+// UpdateMyProfile updates the saved applicant profile of the authenticated user.
+//
+// @Summary Update saved profile
+// @Description Updates the applicant profile used by all draft applications and by applications started later.
+// @Description Submitted applications keep the profile they were submitted with.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.StudentProfileInput true "Profile data"
+// @Success 200 {object} models.SuccessResponse{data=models.StudentProfile} "Profile updated successfully"
+// @Failure 400 {object} models.ErrorResponse{details=[]models.FieldError} "Invalid input"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Failed to update profile"
+// @Router /users/me/profile [put]
+func UpdateMyProfile(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var input models.StudentProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var profile models.StudentProfile
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		saved, err := utils.EnsureApplicantProfile(tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := utils.UpdateStudentProfile(tx, saved, input); err != nil {
+			return err
+		}
+		return utils.PreloadStudentProfile(tx).First(&profile, saved.ID).Error
+	})
+	var invalid utils.ValidationErrors
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input",
+			Error:   "one or more fields are invalid",
+			Details: invalid,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Profile updated successfully",
+		Data:    profile,
+	})
+}
@@ -0,0 +1,616 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errApplicationClaimed = errors.New("application is claimed")
+
+// GetApplicationForReview retrieves any application by ID for staff review.
+//
+// @Summary Get application for review
+// @Description Fetches a submitted application with its full student profile and its latest submission snapshot regardless of its owner. Drafts are not visible. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ReviewApplication} "Application fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch application"
+// @Router /review/applications/{id} [get]
+func GetApplicationForReview(c *gin.Context) {
+	application, ok := findReviewableApplication(c, "User", "StudentProfile", "StudentProfile.Addresses",
+		"StudentProfile.EducationHistory", "StudentProfile.Documents")
+	if !ok {
+		return
+	}
+
+	// Reviewers decide on what was submitted, which the live profile may no longer match
+	review := models.ReviewApplication{Application: *application}
+	var latest models.ApplicationSnapshot
+	err := db.DB.Where("application_id = ?", application.ID).Order("version DESC").First(&latest).Error
+	switch {
+	case err == nil:
+		review.LatestSnapshot = &latest
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch application",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application fetched successfully",
+		Data:    review,
+	})
+}
+
+// TransitionApplicationStatus moves an application to a new review state.
+//
+// @Summary Change application status
+// @Description Moves an application through the review workflow (submitted → under_review → approved/rejected/needs_info). Rejections and information requests need a reason. Cases assigned to another reviewer can only be moved by an admin; starting the review of an unassigned case claims it. Reviewer or admin only.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body models.TransitionRequest true "Target status and reason"
+// @Success 200 {object} models.SuccessResponse "Application status updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request, unknown status or missing reason"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Illegal status transition or case assigned to another reviewer"
+// @Failure 500 {object} models.ErrorResponse "Failed to update application status"
+// @Router /review/applications/{id}/status [post]
+func TransitionApplicationStatus(c *gin.Context) {
+	var req models.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !req.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Unknown application status",
+			Error:   "unknown status " + string(req.Status),
+		})
+		return
+	}
+
+	id, ok := pathID(c, "application")
+	if !ok {
+		return
+	}
+
+	var application models.Application
+	reviewerID := c.GetUint("user_id")
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// The row stays locked until the change is committed, so the assignment checked here
+		// cannot change under a concurrent claim, release or transition
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, id).Error; err != nil {
+			return err
+		}
+		if assignedToAnotherReviewer(c, &application) {
+			return errApplicationClaimed
+		}
+		// Starting a review claims an unassigned case for the reviewer
+		if application.AssignedReviewerID == nil && req.Status == models.ApplicationStatusUnderReview {
+			now := time.Now()
+			application.AssignedReviewerID = &reviewerID
+			application.AssignedAt = &now
+		}
+		return utils.TransitionApplication(tx, &application, req.Status, models.PermissionApplicationReview, reviewerID, req.Reason)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Application not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, errApplicationClaimed) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Application is assigned to another reviewer",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, models.ErrReasonRequired) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Reason required",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, models.ErrIllegalTransition) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Illegal status transition",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update application status",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application status updated successfully",
+		Data:    application,
+	})
+}
+
+// GetApplicationHistoryForReview retrieves the status timeline of any application.
+//
+// @Summary Get application history for review
+// @Description Returns every status change of the application in chronological order, including the actor and reason. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.ApplicationStatusHistory} "Application history fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch application history"
+// @Router /review/applications/{id}/history [get]
+func GetApplicationHistoryForReview(c *gin.Context) {
+	if application, ok := findReviewableApplication(c); ok {
+		respondStatusHistory(c, application.ID)
+	}
+}
+
+// ListReviewQueue lists non-draft applications across all users.
+//
+// @Summary List review queue
+// @Description Lists submitted applications of all users, oldest submission first, with filtering and pagination. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param scheme_id query int false "Scheme ID"
+// @Param status query string false "Application status"
+// @Param submitted_after query string false "Submitted on or after (RFC 3339)"
+// @Param submitted_before query string false "Submitted on or before (RFC 3339)"
+// @Param category query string false "Applicant category"
+// @Param assigned_to query string false "Reviewer ID, me or unassigned"
+// @Success 200 {object} models.ApplicationResponse "Applications fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid query parameters"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch applications"
+// @Router /review/applications [get]
+func ListReviewQueue(c *gin.Context) {
+	pagination, offset := utils.GetPagination(c)
+
+	var filter models.ApplicationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	query := db.DB.
+		Model(&models.Application{}).
+		Joins("JOIN student_profiles ON student_profiles.id = applications.student_profile_id").
+		Where("applications.status <> ?", models.ApplicationStatusDraft)
+
+	query, err := utils.ApplyApplicationFilters(query, filter, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch total count",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	meta := utils.BuildPaginationMeta(c, pagination, totalCount)
+
+	var applications []models.Application
+	if err := query.
+		Preload("User").
+		Preload("StudentProfile").
+		Order("applications.submitted_at ASC").
+		Offset(int(offset)).Limit(int(pagination.Limit)).
+		Find(&applications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch applications",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApplicationResponse{
+		Code:    http.StatusOK,
+		Message: "Applications fetched successfully",
+		Data:    applications,
+		Meta:    meta,
+	})
+}
+
+// AssignApplication assigns an application to a specific reviewer.
+//
+// @Summary Assign application
+// @Description Assigns an application to an active reviewer or admin. Reviewers can only assign unassigned cases and their own; admins can reassign any. Reviewer or admin only.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param request body models.AssignReviewerRequest true "Reviewer to assign"
+// @Success 200 {object} models.SuccessResponse "Application assigned successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request or user cannot review"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application is assigned to another reviewer"
+// @Failure 500 {object} models.ErrorResponse "Failed to assign application"
+// @Router /review/applications/{id}/assign [post]
+func AssignApplication(c *gin.Context) {
+	var req models.AssignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var reviewer models.User
+	if err := db.DB.First(&reviewer, req.ReviewerID).Error; err != nil || !reviewer.IsActive ||
+		!reviewer.Role.HasPermission(models.PermissionApplicationReview) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "User cannot review applications",
+			Error:   "invalid reviewer",
+		})
+		return
+	}
+
+	application, ok := findReviewableApplication(c)
+	if !ok {
+		return
+	}
+
+	// Reviewers may hand over unassigned cases and their own; only admins can take a case
+	// away from another reviewer
+	now := time.Now()
+	query := db.DB.Model(&models.Application{}).Where("id = ?", application.ID)
+	if !isAdmin(c) {
+		query = query.Where("assigned_reviewer_id IS NULL OR assigned_reviewer_id = ?", c.GetUint("user_id"))
+	}
+	result := query.Updates(map[string]interface{}{
+		"assigned_reviewer_id": reviewer.ID,
+		"assigned_at":          &now,
+	})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to assign application",
+			Error:   result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Application is assigned to another reviewer",
+			Error:   errApplicationClaimed.Error(),
+		})
+		return
+	}
+
+	application.AssignedReviewerID = &reviewer.ID
+	application.AssignedAt = &now
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application assigned successfully",
+		Data:    application,
+	})
+}
+
+// ClaimApplication assigns an unassigned application to the calling reviewer.
+//
+// @Summary Claim application
+// @Description Claims an unassigned application for the caller. Fails if another reviewer already holds it. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse "Application claimed successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application is assigned to another reviewer"
+// @Failure 500 {object} models.ErrorResponse "Failed to claim application"
+// @Router /review/applications/{id}/claim [post]
+func ClaimApplication(c *gin.Context) {
+	application, ok := findReviewableApplication(c)
+	if !ok {
+		return
+	}
+	reviewerID := c.GetUint("user_id")
+
+	// The conditional update makes concurrent claims race-free: only one can match
+	now := time.Now()
+	result := db.DB.Model(&models.Application{}).
+		Where("id = ? AND (assigned_reviewer_id IS NULL OR assigned_reviewer_id = ?)", application.ID, reviewerID).
+		Updates(map[string]interface{}{
+			"assigned_reviewer_id": reviewerID,
+			"assigned_at":          &now,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to claim application",
+			Error:   result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Application is assigned to another reviewer",
+			Error:   errApplicationClaimed.Error(),
+		})
+		return
+	}
+
+	application.AssignedReviewerID = &reviewerID
+	application.AssignedAt = &now
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application claimed successfully",
+		Data:    application,
+	})
+}
+
+// ReleaseApplication removes the reviewer assignment of an application.
+//
+// @Summary Release application
+// @Description Releases a case so another reviewer can claim it. Reviewers can only release their own cases; admins can release any. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse "Application released successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 409 {object} models.ErrorResponse "Application is assigned to another reviewer"
+// @Failure 500 {object} models.ErrorResponse "Failed to release application"
+// @Router /review/applications/{id}/release [post]
+func ReleaseApplication(c *gin.Context) {
+	application, ok := findReviewableApplication(c)
+	if !ok {
+		return
+	}
+
+	query := db.DB.Model(&models.Application{}).Where("id = ?", application.ID)
+	if !isAdmin(c) {
+		query = query.Where("assigned_reviewer_id = ?", c.GetUint("user_id"))
+	}
+	result := query.Updates(map[string]interface{}{
+		"assigned_reviewer_id": nil,
+		"assigned_at":          nil,
+	})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to release application",
+			Error:   result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Application is assigned to another reviewer",
+			Error:   "application is not yours to release",
+		})
+		return
+	}
+
+	application.AssignedReviewerID = nil
+	application.AssignedAt = nil
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Application released successfully",
+		Data:    application,
+	})
+}
+
+// findReviewableApplication loads the non-draft application named by the id path parameter with
+// the given associations, writing a 400, 404 or 500 response if it cannot.
+func findReviewableApplication(c *gin.Context, preloads ...string) (*models.Application, bool) {
+	id, ok := pathID(c, "application")
+	if !ok {
+		return nil, false
+	}
+
+	query := db.DB.Where("status <> ?", models.ApplicationStatusDraft)
+	for _, association := range preloads {
+		query = query.Preload(association)
+	}
+	var application models.Application
+	if err := query.First(&application, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Application not found",
+				Error:   err.Error(),
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch application",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	return &application, true
+}
+
+// assignedToAnotherReviewer reports whether the application is assigned to a reviewer other
+// than the caller. Admins may act on any case.
+func assignedToAnotherReviewer(c *gin.Context, application *models.Application) bool {
+	return application.AssignedReviewerID != nil && *application.AssignedReviewerID != c.GetUint("user_id") && !isAdmin(c)
+}
+
+// heldByAnotherReviewer writes a 409 response and returns true if the application is assigned
+// to a reviewer other than the caller.
+func heldByAnotherReviewer(c *gin.Context, application *models.Application) bool {
+	if !assignedToAnotherReviewer(c, application) {
+		return false
+	}
+	c.JSON(http.StatusConflict, models.ErrorResponse{
+		Code:    http.StatusConflict,
+		Message: "Application is assigned to another reviewer",
+		Error:   errApplicationClaimed.Error(),
+	})
+	return true
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	return role == models.RoleAdmin
+}
+
+// VerifyDocument records the verification result of a single uploaded document.
+//
+// @Summary Verify document
+// @Description Marks one uploaded document of an application as verified or rejected. Rejections need a reason. The application is verified once all its documents are. Reviewer or admin only.
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param doc_id path string true "Document ID"
+// @Param request body models.VerifyDocumentRequest true "Verification result"
+// @Success 200 {object} models.SuccessResponse{data=models.UploadDocument} "Document verification updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request, status or missing reason"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application or document not found"
+// @Failure 409 {object} models.ErrorResponse "Application is assigned to another reviewer"
+// @Failure 500 {object} models.ErrorResponse "Failed to update document verification"
+// @Router /review/applications/{id}/documents/{doc_id}/verification [put]
+func VerifyDocument(c *gin.Context) {
+	var req models.VerifyDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if req.Status != models.DocumentVerified && req.Status != models.DocumentRejected {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Status must be verified or rejected",
+			Error:   "invalid status " + string(req.Status),
+		})
+		return
+	}
+	if req.Status == models.DocumentRejected && req.Reason == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "A reason is required to reject a document",
+			Error:   "missing reason",
+		})
+		return
+	}
+
+	application, ok := findReviewableApplication(c)
+	if !ok {
+		return
+	}
+	if heldByAnotherReviewer(c, application) {
+		return
+	}
+
+	var document models.UploadDocument
+	if err := db.DB.
+		Where("id = ? AND student_id = ?", c.Param("doc_id"), application.StudentProfileID).
+		First(&document).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Document not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	reviewerID := c.GetUint("user_id")
+	now := time.Now()
+	document.VerificationStatus = req.Status
+	document.RejectionReason = ""
+	if req.Status == models.DocumentRejected {
+		document.RejectionReason = req.Reason
+	}
+	document.VerifiedByID = &reviewerID
+	document.VerifiedAt = &now
+
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("StudentProfile").Save(&document).Error; err != nil {
+			return err
+		}
+		return utils.RefreshApplicationVerified(tx, application)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update document verification",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Document verification updated successfully",
+		Data:    document,
+	})
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetApplicationForReviewIncludesLatestSnapshot(t *testing.T) {
+	useTestDB(t)
+	useTestStorage(t)
+	user, application := createDraftApplication(t)
+
+	content := []byte("%PDF-1.4\nsubmitted")
+	if code, _ := uploadFile(t, user.ID, application.ID, "aadhar_card", content); code != http.StatusCreated {
+		t.Fatalf("upload returned %d", code)
+	}
+
+	get := func() *httptest.ResponseRecorder {
+		t.Helper()
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.GET("/review/applications/:id", GetApplicationForReview)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/review/applications/"+strconv.FormatUint(uint64(application.ID), 10), nil))
+		return w
+	}
+	getForReview := func() models.ReviewApplication {
+		t.Helper()
+		w := get()
+		if w.Code != http.StatusOK {
+			t.Fatalf("review GET returned %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.ReviewApplication `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response.Data
+	}
+
+	if w := get(); w.Code != http.StatusNotFound {
+		t.Errorf("review GET of a draft returned %d, want 404", w.Code)
+	}
+	// Move the application out of draft without submitting it, which would take a snapshot
+	if err := db.DB.Model(application).Update("status", models.ApplicationStatusSubmitted).Error; err != nil {
+		t.Fatal(err)
+	}
+	if review := getForReview(); review.LatestSnapshot != nil {
+		t.Errorf("application without snapshots has snapshot version %d", review.LatestSnapshot.Version)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := utils.CreateApplicationSnapshot(db.DB, application, user.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	review := getForReview()
+	if review.ID != application.ID || review.LatestSnapshot == nil || review.LatestSnapshot.Version != 2 {
+		t.Fatalf("review = application %d with snapshot %+v, want version 2", review.ID, review.LatestSnapshot)
+	}
+
+	var data models.ApplicationSnapshotData
+	if err := json.Unmarshal(review.LatestSnapshot.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Profile.Documents) != 1 {
+		t.Fatalf("snapshot has %d documents, want 1", len(data.Profile.Documents))
+	}
+	doc := data.Profile.Documents[0]
+	if doc.ID == 0 || doc.StorageKey == "" {
+		t.Errorf("snapshot document = %+v, want its ID and storage key", doc)
+	}
+	if w := download(t, doc.URL); w.Code != http.StatusOK || w.Body.String() != string(content) {
+		t.Errorf("snapshot document link %q returned %d", doc.URL, w.Code)
+	}
+
+	var stored models.ApplicationSnapshot
+	if err := db.DB.Where("application_id = ? AND version = 2", application.ID).First(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(stored.Data), "signature=") {
+		t.Errorf("stored snapshot holds a signed link: %s", stored.Data)
+	}
+}
+
+// createSubmittedApplication stores a complete application and submits it through the API.
+func createSubmittedApplication(t *testing.T, username string) (*models.User, *models.Application) {
+	t.Helper()
+	user, application := createCompleteApplication(t, username)
+	if code, report := submitApplication(t, user, application); code != http.StatusOK {
+		t.Fatalf("submit returned %d with %+v", code, report)
+	}
+	return user, application
+}
+
+// transition moves an application through the review API and returns the response code.
+func transition(t *testing.T, user *models.User, application *models.Application, to models.ApplicationStatus, reason string) int {
+	t.Helper()
+	path := "/review/applications/" + strconv.FormatUint(uint64(application.ID), 10) + "/status"
+	return callAPI(t, user, http.MethodPost, path, models.TransitionRequest{Status: to, Reason: reason}, nil).Code
+}
+
+func TestTransitionApplicationStatus(t *testing.T) {
+	useTestDB(t)
+	_, application := createSubmittedApplication(t, "asha")
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+	other := createUser(t, "meera", models.RoleReviewer)
+	admin := createUser(t, "admin", models.RoleAdmin)
+
+	if code := transition(t, reviewer, application, models.ApplicationStatusApproved, ""); code != http.StatusConflict {
+		t.Errorf("approving a submitted application returned %d, want 409", code)
+	}
+	if code := transition(t, reviewer, application, models.ApplicationStatusUnderReview, ""); code != http.StatusOK {
+		t.Fatalf("starting the review returned %d", code)
+	}
+	var stored models.Application
+	if err := db.DB.First(&stored, application.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.AssignedReviewerID == nil || *stored.AssignedReviewerID != reviewer.ID {
+		t.Fatalf("starting the review assigned %v, want reviewer %d", stored.AssignedReviewerID, reviewer.ID)
+	}
+
+	if code := transition(t, other, application, models.ApplicationStatusApproved, ""); code != http.StatusConflict {
+		t.Errorf("another reviewer approving returned %d, want 409", code)
+	}
+	if code := transition(t, reviewer, application, models.ApplicationStatusRejected, ""); code != http.StatusBadRequest {
+		t.Errorf("rejecting without a reason returned %d, want 400", code)
+	}
+	if code := transition(t, admin, application, models.ApplicationStatusNeedsInfo, "upload a clearer Aadhaar card"); code != http.StatusOK {
+		t.Errorf("admin requesting information returned %d, want 200", code)
+	}
+	if code := callAPI(t, reviewer, http.MethodPost, "/review/applications/999999/status",
+		models.TransitionRequest{Status: models.ApplicationStatusUnderReview}, nil).Code; code != http.StatusNotFound {
+		t.Errorf("moving a missing application returned %d, want 404", code)
+	}
+
+	var history []models.ApplicationStatusHistory
+	if err := db.DB.Where("application_id = ?", application.ID).Order("id").Find(&history).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := []models.ApplicationStatus{models.ApplicationStatusSubmitted, models.ApplicationStatusUnderReview, models.ApplicationStatusNeedsInfo}
+	if len(history) != len(want) {
+		t.Fatalf("history has %d entries, want %d", len(history), len(want))
+	}
+	for i, entry := range history {
+		if entry.ToStatus != want[i] || (i > 0 && entry.FromStatus != history[i-1].ToStatus) {
+			t.Errorf("history[%d] = %s -> %s, want a chain ending in %s", i, entry.FromStatus, entry.ToStatus, want[i])
+		}
+	}
+}
+
+func TestTransitionApplicationRefusesStaleStatus(t *testing.T) {
+	useTestDB(t)
+	user, application := createSubmittedApplication(t, "asha")
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+
+	// Both copies were read while the application was submitted
+	var stale models.Application
+	if err := db.DB.First(&stale, application.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := transition(t, reviewer, application, models.ApplicationStatusUnderReview, ""); code != http.StatusOK {
+		t.Fatalf("starting the review returned %d", code)
+	}
+
+	err := utils.TransitionApplication(db.DB, &stale, models.ApplicationStatusWithdrawn, models.PermissionApplicationOwn, user.ID, "")
+	if !errors.Is(err, models.ErrIllegalTransition) {
+		t.Fatalf("transition from a stale status = %v, want ErrIllegalTransition", err)
+	}
+	var stored models.Application
+	if err := db.DB.First(&stored, application.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.ApplicationStatusUnderReview {
+		t.Errorf("status = %s after a stale transition, want %s", stored.Status, models.ApplicationStatusUnderReview)
+	}
+}
+
+func TestReviewQueueFilters(t *testing.T) {
+	useTestDB(t)
+	_, first := createSubmittedApplication(t, "asha")
+	_, second := createSubmittedApplication(t, "kiran")
+	createCompleteApplication(t, "meera")
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+
+	if code := callAPI(t, reviewer, http.MethodPost, "/review/applications/"+strconv.FormatUint(uint64(first.ID), 10)+"/claim", nil, nil).Code; code != http.StatusOK {
+		t.Fatalf("claim returned %d", code)
+	}
+
+	tests := []struct {
+		query string
+		want  []uint
+	}{
+		{"", []uint{first.ID, second.ID}},
+		{"?status=submitted", []uint{first.ID, second.ID}},
+		{"?status=draft", nil},
+		{"?assigned_to=me", []uint{first.ID}},
+		{"?assigned_to=unassigned", []uint{second.ID}},
+		{"?assigned_to=" + strconv.FormatUint(uint64(reviewer.ID), 10), []uint{first.ID}},
+		{"?scheme_id=" + strconv.FormatUint(uint64(second.SchemeID), 10), []uint{second.ID}},
+		{"?category=SC", nil},
+	}
+	for _, tt := range tests {
+		var applications []models.Application
+		w := callAPI(t, reviewer, http.MethodGet, "/review/applications"+tt.query, nil, &applications)
+		if w.Code != http.StatusOK {
+			t.Errorf("queue%s returned %d: %s", tt.query, w.Code, w.Body.String())
+			continue
+		}
+		var got []uint
+		for _, application := range applications {
+			got = append(got, application.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("queue%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if code := callAPI(t, reviewer, http.MethodGet, "/review/applications?assigned_to=someone", nil, nil).Code; code != http.StatusBadRequest {
+		t.Errorf("queue with an invalid assignee returned %d, want 400", code)
+	}
+}
+
+func TestClaimAssignAndRelease(t *testing.T) {
+	useTestDB(t)
+	applicant, application := createSubmittedApplication(t, "asha")
+	_, draft := createCompleteApplication(t, "meera")
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+	other := createUser(t, "kiran", models.RoleReviewer)
+	admin := createUser(t, "admin", models.RoleAdmin)
+
+	path := func(application *models.Application, action string) string {
+		return "/review/applications/" + strconv.FormatUint(uint64(application.ID), 10) + "/" + action
+	}
+	assign := func(user *models.User, to uint) int {
+		return callAPI(t, user, http.MethodPost, path(application, "assign"), models.AssignReviewerRequest{ReviewerID: to}, nil).Code
+	}
+	assigned := func() *uint {
+		var stored models.Application
+		if err := db.DB.First(&stored, application.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return stored.AssignedReviewerID
+	}
+
+	steps := []struct {
+		name string
+		code int
+		want int
+	}{
+		{"reviewer claims", callAPI(t, reviewer, http.MethodPost, path(application, "claim"), nil, nil).Code, http.StatusOK},
+		{"reviewer claims again", callAPI(t, reviewer, http.MethodPost, path(application, "claim"), nil, nil).Code, http.StatusOK},
+		{"other reviewer claims", callAPI(t, other, http.MethodPost, path(application, "claim"), nil, nil).Code, http.StatusConflict},
+		{"other reviewer releases", callAPI(t, other, http.MethodPost, path(application, "release"), nil, nil).Code, http.StatusConflict},
+		{"other reviewer takes it", assign(other, other.ID), http.StatusConflict},
+		{"assign to an applicant", assign(reviewer, applicant.ID), http.StatusBadRequest},
+		{"reviewer hands it over", assign(reviewer, other.ID), http.StatusOK},
+		{"former holder releases", callAPI(t, reviewer, http.MethodPost, path(application, "release"), nil, nil).Code, http.StatusConflict},
+		{"admin releases", callAPI(t, admin, http.MethodPost, path(application, "release"), nil, nil).Code, http.StatusOK},
+	}
+	for _, step := range steps {
+		if step.code != step.want {
+			t.Errorf("%s returned %d, want %d", step.name, step.code, step.want)
+		}
+	}
+	if id := assigned(); id != nil {
+		t.Errorf("application is assigned to %d after the admin released it", *id)
+	}
+
+	if code := assign(reviewer, reviewer.ID); code != http.StatusOK {
+		t.Fatalf("reviewer assigning themselves returned %d", code)
+	}
+	if code := assign(admin, other.ID); code != http.StatusOK {
+		t.Errorf("admin reassigning returned %d, want 200", code)
+	}
+	if id := assigned(); id == nil || *id != other.ID {
+		t.Errorf("application is assigned to %v, want reviewer %d", id, other.ID)
+	}
+
+	if code := callAPI(t, reviewer, http.MethodPost, path(draft, "claim"), nil, nil).Code; code != http.StatusNotFound {
+		t.Errorf("claiming a draft returned %d, want 404", code)
+	}
+	if code := callAPI(t, reviewer, http.MethodPost, "/review/applications/1%20OR%201=1/claim", nil, nil).Code; code != http.StatusBadRequest {
+		t.Errorf("claiming an invalid id returned %d, want 400", code)
+	}
+	if code := callAPI(t, applicant, http.MethodPost, path(application, "claim"), nil, nil).Code; code != http.StatusForbidden {
+		t.Errorf("applicant claiming returned %d, want 403", code)
+	}
+}
+
+func TestVerifyDocument(t *testing.T) {
+	useTestDB(t)
+	applicant, application := createSubmittedApplication(t, "asha")
+	_, draft := createCompleteApplication(t, "meera")
+	reviewer := createUser(t, "ravi", models.RoleReviewer)
+	other := createUser(t, "kiran", models.RoleReviewer)
+
+	// Submitting moved the application to its own copy of the profile
+	var stored models.Application
+	if err := db.DB.First(&stored, application.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	var document models.UploadDocument
+	if err := db.DB.Where("student_id = ? AND name = ?", stored.StudentProfileID, "aadhar_card").First(&document).Error; err != nil {
+		t.Fatal(err)
+	}
+	var draftDocument models.UploadDocument
+	if err := db.DB.Where("student_id = ?", draft.StudentProfileID).First(&draftDocument).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(user *models.User, applicationID, documentID uint, req models.VerifyDocumentRequest) int {
+		path := "/review/applications/" + strconv.FormatUint(uint64(applicationID), 10) +
+			"/documents/" + strconv.FormatUint(uint64(documentID), 10) + "/verification"
+		return callAPI(t, user, http.MethodPut, path, req, nil).Code
+	}
+	rejected := models.VerifyDocumentRequest{Status: models.DocumentRejected, Reason: "Scan is blurry"}
+	verified := models.VerifyDocumentRequest{Status: models.DocumentVerified}
+
+	steps := []struct {
+		name string
+		code int
+		want int
+	}{
+		{"reject without reason", verify(reviewer, application.ID, document.ID, models.VerifyDocumentRequest{Status: models.DocumentRejected}), http.StatusBadRequest},
+		{"reset to pending", verify(reviewer, application.ID, document.ID, models.VerifyDocumentRequest{Status: models.DocumentPending}), http.StatusBadRequest},
+		{"document of a draft", verify(reviewer, draft.ID, draftDocument.ID, verified), http.StatusNotFound},
+		{"document of another profile", verify(reviewer, application.ID, draftDocument.ID, verified), http.StatusNotFound},
+		{"applicant verifies", verify(applicant, application.ID, document.ID, verified), http.StatusForbidden},
+		{"reviewer starts the review", transition(t, reviewer, application, models.ApplicationStatusUnderReview, ""), http.StatusOK},
+		{"other reviewer verifies", verify(other, application.ID, document.ID, verified), http.StatusConflict},
+		{"reviewer rejects", verify(reviewer, application.ID, document.ID, rejected), http.StatusOK},
+	}
+	for _, step := range steps {
+		if step.code != step.want {
+			t.Errorf("%s returned %d, want %d", step.name, step.code, step.want)
+		}
+	}
+
+	reload := func() (models.UploadDocument, models.Application) {
+		t.Helper()
+		var current models.UploadDocument
+		if err := db.DB.First(&current, document.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		var application models.Application
+		if err := db.DB.First(&application, stored.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return current, application
+	}
+
+	current, stored := reload()
+	if current.VerificationStatus != models.DocumentRejected || current.RejectionReason != rejected.Reason ||
+		current.VerifiedByID == nil || *current.VerifiedByID != reviewer.ID {
+		t.Errorf("rejected document = %+v", current)
+	}
+	if stored.Verified {
+		t.Error("application is verified with a rejected document")
+	}
+
+	if code := verify(reviewer, application.ID, document.ID, verified); code != http.StatusOK {
+		t.Fatalf("verifying returned %d", code)
+	}
+	current, stored = reload()
+	if current.VerificationStatus != models.DocumentVerified || current.RejectionReason != "" {
+		t.Errorf("verified document = %+v, want no rejection reason", current)
+	}
+	if !stored.Verified {
+		t.Error("application is not verified once all its documents are")
+	}
+}
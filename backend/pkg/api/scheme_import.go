@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportSchemes creates or updates schemes from a JSON, YAML or CSV file.
+//
+// @Summary Import schemes
+// @Description Upserts complete scheme definitions, including eligibility and document requirements, by scheme name.
+// @Description The file is sent as the multipart field "file" or as the raw request body. The format is taken from the
+// @Description format parameter, the file extension or the content type. Every row is validated and reported;
+// @Description nothing is saved if any row fails or dry_run is set.
+// @Tags scheme
+// @Accept json,mpfd,plain
+// @Produce json
+// @Param format query string false "File format" Enums(json, yaml, csv)
+// @Param dry_run query bool false "Validate without saving"
+// @Param file formData file false "Scheme file"
+// @Success 200 {object} models.SuccessResponse{data=models.SchemeImportReport} "Import report"
+// @Failure 400 {object} models.ErrorResponse{details=models.SchemeImportReport} "Invalid file or rows"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 413 {object} models.ErrorResponse "File too large"
+// @Failure 500 {object} models.ErrorResponse "Failed to import schemes"
+// @Router /schemes/import [post]
+func ImportSchemes(c *gin.Context) {
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid dry_run value",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	data, formatHint, err := readSchemeFile(c)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: fmt.Sprintf("File is larger than %d MB", utils.MaxSchemeImportSize>>20),
+				Error:   "file too large",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to read scheme file",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if hint := c.Query("format"); hint != "" {
+		formatHint = hint
+	}
+	format, err := utils.ParseSchemeFormat(formatHint)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Unsupported file format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	records, err := utils.DecodeSchemes(bytes.NewReader(data), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid scheme file",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	report, err := utils.ImportSchemes(db.DB, records, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to import schemes",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if report.Failed > 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Some schemes are invalid, nothing was saved",
+			Error:   fmt.Sprintf("%d of %d rows failed", report.Failed, len(report.Rows)),
+			Details: report,
+		})
+		return
+	}
+
+	message := "Schemes imported successfully"
+	if dryRun {
+		message = "All schemes are valid, nothing was saved"
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// ExportSchemes writes all schemes with their eligibility and document requirements as a file.
+//
+// @Summary Export schemes
+// @Description Downloads all schemes in a format accepted by the import endpoint.
+// @Tags scheme
+// @Produce json,plain
+// @Param format query string false "File format" Enums(json, yaml, csv) default(json)
+// @Success 200 {array} models.SchemeInput "Scheme definitions"
+// @Failure 400 {object} models.ErrorResponse "Unsupported file format"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Failed to export schemes"
+// @Router /schemes/export [get]
+func ExportSchemes(c *gin.Context) {
+	format, err := utils.ParseSchemeFormat(c.DefaultQuery("format", string(utils.SchemeFormatJSON)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Unsupported file format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var schemes []models.Scheme
+	if err := db.DB.
+		Preload("Eligibility.DocumentMappings.Document").
+		Order("id ASC").
+		Find(&schemes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to export schemes",
+			Error:   err.Error(),
+		})
+		return
+	}
+	inputs := make([]models.SchemeInput, 0, len(schemes))
+	for i := range schemes {
+		inputs = append(inputs, utils.SchemeToInput(&schemes[i]))
+	}
+
+	var out bytes.Buffer
+	if err := utils.EncodeSchemes(&out, format, inputs); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to export schemes",
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "schemes."+string(format)))
+	c.Data(http.StatusOK, schemeContentTypes[format], out.Bytes())
+}
+
+var schemeContentTypes = map[utils.SchemeFormat]string{
+	utils.SchemeFormatJSON: "application/json",
+	utils.SchemeFormatYAML: "application/yaml",
+	utils.SchemeFormatCSV:  "text/csv",
+}
+
+// readSchemeFile reads an uploaded scheme file from the multipart field "file" or from the raw body.
+// It returns the content and a format hint: the file name or the content type.
+func readSchemeFile(c *gin.Context) ([]byte, string, error) {
+	// Leave some room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, utils.MaxSchemeImportSize+1<<20)
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		if fileHeader.Size > utils.MaxSchemeImportSize {
+			return nil, "", &http.MaxBytesError{Limit: utils.MaxSchemeImportSize}
+		}
+		f, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, fileHeader.Filename, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > utils.MaxSchemeImportSize {
+		return nil, "", &http.MaxBytesError{Limit: utils.MaxSchemeImportSize}
+	}
+	if len(data) == 0 {
+		return nil, "", errors.New("request body is empty")
+	}
+	return data, c.ContentType(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ChayanDass/beneficiary-manager/pkg/db"
 	"github.com/ChayanDass/beneficiary-manager/pkg/models"
@@ -78,7 +79,10 @@ func GetSchemes(c *gin.Context) {
 }
 
 func GetSchemeByID(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := pathID(c, "scheme")
+	if !ok {
+		return
+	}
 	var scheme models.Scheme
 
 	if err := db.DB.
@@ -123,7 +127,10 @@ func GetSchemeByID(c *gin.Context) {
 // @Router /api/schemes/status/{id} [get]
 func GetSchemeStatus(c *gin.Context) {
 	// Extract parameters from URL
-	id := c.Param("id")
+	id, ok := pathID(c, "scheme")
+	if !ok {
+		return
+	}
 	var scheme models.Scheme
 
 	err := db.DB.Where("id = ?", id).First(&scheme).Error
@@ -151,3 +158,309 @@ func GetSchemeStatus(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, res)
 }
+
+// GetEligibleSchemes lists the schemes the caller's saved profile qualifies for.
+//
+// @Summary Get eligible schemes
+// @Description Evaluates the caller's most recently saved student profile against every scheme that has not closed. Eligible schemes are ranked by amount and closing date; schemes missed by a single criterion are returned as near misses with the reason.
+// @Tags scheme
+// @Produce json
+// @Success 200 {object} models.SuccessResponse{data=models.SchemeRecommendations} "Eligible schemes fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "No saved student profile"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch schemes"
+// @Router /schemes/eligible [get]
+func GetEligibleSchemes(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	profile, err := utils.FindApplicantProfile(db.DB, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "No saved student profile found, start an application first",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch student profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	var schemes []models.Scheme
+	if err := db.DB.
+		Preload("Eligibility").
+		Preload("Eligibility.DocumentMappings").
+		Preload("Eligibility.DocumentMappings.Document").
+		Where("status <> ? AND end_date >= ?", models.SchemeStatusClosed, now).
+		Find(&schemes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch schemes",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Eligible schemes fetched successfully",
+		Data:    utils.RecommendSchemes(profile, schemes, now),
+	})
+}
+
+// CreateScheme creates a scheme together with its eligibility criteria and document requirements.
+//
+// @Summary Create scheme
+// @Description Creates a scheme, its eligibility and its required-document mappings in a single transaction.
+// @Tags scheme
+// @Accept json
+// @Produce json
+// @Param request body models.SchemeInput true "Scheme definition"
+// @Success 201 {object} models.SuccessResponse "Scheme created successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid scheme definition"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Failed to create scheme"
+// @Router /schemes [post]
+func CreateScheme(c *gin.Context) {
+	var input models.SchemeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	scheme, err := utils.CreateScheme(db.DB, input)
+	if err != nil {
+		respondSchemeWriteError(c, err, "Failed to create scheme")
+		return
+	}
+
+	created, err := findScheme(db.DB, scheme.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch scheme",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Scheme created successfully",
+		Data:    created,
+	})
+}
+
+// ReplaceScheme replaces a scheme, its eligibility criteria and all of its document requirements.
+//
+// @Summary Replace scheme
+// @Description Fully replaces a scheme definition, including eligibility and required documents, in a single transaction.
+// @Tags scheme
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param request body models.SchemeInput true "Scheme definition"
+// @Success 200 {object} models.SuccessResponse "Scheme updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid scheme definition"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Scheme not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to update scheme"
+// @Router /schemes/{id} [put]
+func ReplaceScheme(c *gin.Context) {
+	var input models.SchemeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	updateScheme(c, func(tx *gorm.DB, scheme *models.Scheme) error {
+		return utils.ReplaceScheme(tx, scheme, input)
+	})
+}
+
+// PatchScheme partially updates a scheme and its eligibility criteria.
+//
+// @Summary Patch scheme
+// @Description Updates only the provided scheme and eligibility fields. A provided documents_required list replaces the existing mappings.
+// @Tags scheme
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param request body models.SchemePatchInput true "Fields to update"
+// @Success 200 {object} models.SuccessResponse "Scheme updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid scheme definition"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Scheme not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to update scheme"
+// @Router /schemes/{id} [patch]
+func PatchScheme(c *gin.Context) {
+	var input models.SchemePatchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	updateScheme(c, func(tx *gorm.DB, scheme *models.Scheme) error {
+		utils.ApplySchemePatch(scheme, input)
+		if err := utils.ValidateScheme(scheme); err != nil {
+			return err
+		}
+		if err := utils.SaveScheme(tx, scheme); err != nil {
+			return err
+		}
+		if input.Eligibility != nil && input.Eligibility.Documents != nil {
+			return utils.ReplaceEligibilityDocuments(tx, scheme.EligibilityID, *input.Eligibility.Documents)
+		}
+		return nil
+	})
+}
+
+// DeleteScheme soft-deletes a scheme.
+//
+// @Summary Delete scheme
+// @Description Soft-deletes a scheme so it no longer appears in listings. Existing applications keep their reference.
+// @Tags scheme
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 200 {object} models.SuccessResponse "Scheme deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid scheme ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Scheme not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to delete scheme"
+// @Router /schemes/{id} [delete]
+func DeleteScheme(c *gin.Context) {
+	id, ok := pathID(c, "scheme")
+	if !ok {
+		return
+	}
+	var scheme models.Scheme
+
+	if err := db.DB.First(&scheme, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Scheme not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch scheme",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := db.DB.Delete(&scheme).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to delete scheme",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Scheme deleted successfully",
+	})
+}
+
+// updateScheme loads the scheme named by the id path parameter, applies update inside a
+// transaction and writes the reloaded scheme as the response.
+func updateScheme(c *gin.Context, update func(tx *gorm.DB, scheme *models.Scheme) error) {
+	id, ok := pathID(c, "scheme")
+	if !ok {
+		return
+	}
+	var schemeID uint
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var scheme models.Scheme
+		if err := tx.Preload("Eligibility").First(&scheme, id).Error; err != nil {
+			return err
+		}
+		schemeID = scheme.ID
+		return update(tx, &scheme)
+	})
+	if err != nil {
+		respondSchemeWriteError(c, err, "Failed to update scheme")
+		return
+	}
+
+	updated, err := findScheme(db.DB, schemeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch scheme",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Scheme updated successfully",
+		Data:    updated,
+	})
+}
+
+// findScheme loads a scheme with its eligibility and document mappings.
+func findScheme(tx *gorm.DB, id uint) (*models.Scheme, error) {
+	var scheme models.Scheme
+	if err := tx.
+		Preload("Eligibility").
+		Preload("Eligibility.DocumentMappings").
+		Preload("Eligibility.DocumentMappings.Document").
+		First(&scheme, id).Error; err != nil {
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+func respondSchemeWriteError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Scheme not found",
+			Error:   err.Error(),
+		})
+	case errors.Is(err, utils.ErrInvalidScheme):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid scheme definition",
+			Error:   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func testSchemeInput(name string) models.SchemeInput {
+	return models.SchemeInput{
+		Name:      name,
+		Amount:    5000,
+		StartDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+		Status:    models.SchemeStatusOpen,
+		Eligibility: models.EligibilityInput{
+			Category:  models.CategoryGeneral,
+			AgeMin:    18,
+			Documents: []models.EligibilityDocumentInput{{Name: "aadhar_card", IsMandatory: true}},
+		},
+	}
+}
+
+func TestSchemeCRUD(t *testing.T) {
+	useTestDB(t)
+	admin := createUser(t, "admin", models.RoleAdmin)
+
+	var created models.Scheme
+	if w := callAPI(t, admin, http.MethodPost, "/schemes", testSchemeInput("Merit scholarship"), &created); w.Code != http.StatusCreated {
+		t.Fatalf("create returned %d: %s", w.Code, w.Body.String())
+	}
+	if created.ID == 0 || created.Eligibility.AgeMin != 18 || len(created.Eligibility.DocumentMappings) != 1 {
+		t.Fatalf("created scheme = %+v", created)
+	}
+	path := fmt.Sprintf("/schemes/%d", created.ID)
+
+	replacement := testSchemeInput("Merit scholarship 2025")
+	replacement.Eligibility.Documents = nil
+	var replaced models.Scheme
+	if w := callAPI(t, admin, http.MethodPut, path, replacement, &replaced); w.Code != http.StatusOK {
+		t.Fatalf("replace returned %d: %s", w.Code, w.Body.String())
+	}
+	if replaced.Name != "Merit scholarship 2025" || len(replaced.Eligibility.DocumentMappings) != 0 {
+		t.Errorf("replaced scheme = %+v", replaced)
+	}
+
+	var patched models.Scheme
+	patch := map[string]interface{}{"amount": 7500, "eligibility": map[string]interface{}{"age_max": 25}}
+	if w := callAPI(t, admin, http.MethodPatch, path, patch, &patched); w.Code != http.StatusOK {
+		t.Fatalf("patch returned %d: %s", w.Code, w.Body.String())
+	}
+	if patched.Amount != 7500 || patched.Eligibility.AgeMax != 25 || patched.Eligibility.AgeMin != 18 {
+		t.Errorf("patched scheme = amount %v, ages %d-%d", patched.Amount, patched.Eligibility.AgeMin, patched.Eligibility.AgeMax)
+	}
+
+	if w := callAPI(t, admin, http.MethodDelete, path, nil, nil); w.Code != http.StatusOK {
+		t.Fatalf("delete returned %d", w.Code)
+	}
+	if w := callAPI(t, nil, http.MethodGet, path, nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("GET of a deleted scheme returned %d, want 404", w.Code)
+	}
+}
+
+func TestSchemeWriteErrors(t *testing.T) {
+	useTestDB(t)
+	admin := createUser(t, "admin", models.RoleAdmin)
+
+	invalid := testSchemeInput("Backwards scheme")
+	invalid.EndDate = invalid.StartDate.AddDate(0, 0, -1)
+	if w := callAPI(t, admin, http.MethodPost, "/schemes", invalid, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("scheme ending before it starts returned %d, want 400", w.Code)
+	}
+	if w := callAPI(t, admin, http.MethodPut, "/schemes/999999", testSchemeInput("Missing"), nil); w.Code != http.StatusNotFound {
+		t.Errorf("replacing a missing scheme returned %d, want 404", w.Code)
+	}
+
+	// Non-numeric ids must not reach the query as SQL
+	for _, path := range []string{"/schemes/1%20OR%201=1", "/schemes/abc", "/schemes/0"} {
+		if w := callAPI(t, nil, http.MethodGet, path, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s returned %d, want 400", path, w.Code)
+		}
+		if w := callAPI(t, admin, http.MethodDelete, path, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("DELETE %s returned %d, want 400", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetApplicationSnapshots lists the submission snapshots of an application owned by the authenticated user.
+//
+// @Summary List application snapshots
+// @Description Lists the versions frozen at every submission of the application, oldest first, without their data.
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.ApplicationSnapshot} "Snapshots fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch snapshots"
+// @Router /applications/{id}/snapshots [get]
+func GetApplicationSnapshots(c *gin.Context) {
+	if application, ok := findOwnApplication(c); ok {
+		respondSnapshots(c, application.ID)
+	}
+}
+
+// GetApplicationSnapshot returns one submission snapshot of an application owned by the authenticated user.
+//
+// @Summary Get application snapshot
+// @Description Returns the application data exactly as it was at one submission.
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param version path int true "Snapshot version"
+// @Success 200 {object} models.SuccessResponse{data=models.ApplicationSnapshot} "Snapshot fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application or snapshot not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch snapshot"
+// @Router /applications/{id}/snapshots/{version} [get]
+func GetApplicationSnapshot(c *gin.Context) {
+	if application, ok := findOwnApplication(c); ok {
+		respondSnapshot(c, application.ID)
+	}
+}
+
+// DiffApplicationSnapshots compares two submissions of an application owned by the authenticated user.
+//
+// @Summary Diff application snapshots
+// @Description Lists what changed between two submissions, e.g. after a withdrawn application was resubmitted.
+// @Description Without parameters the latest submission is compared with the one before it.
+// @Tags Applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param from query int false "Older snapshot version"
+// @Param to query int false "Newer snapshot version"
+// @Success 200 {object} models.SuccessResponse{data=models.SnapshotDiff} "Snapshots compared successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID or versions"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 404 {object} models.ErrorResponse "Application or snapshot not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to compare snapshots"
+// @Router /applications/{id}/snapshots/diff [get]
+func DiffApplicationSnapshots(c *gin.Context) {
+	if application, ok := findOwnApplication(c); ok {
+		respondSnapshotDiff(c, application.ID)
+	}
+}
+
+// GetSnapshotsForReview lists the submission snapshots of any application.
+//
+// @Summary List application snapshots for review
+// @Description Lists the versions frozen at every submission of the application, oldest first, without their data. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.ApplicationSnapshot} "Snapshots fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch snapshots"
+// @Router /review/applications/{id}/snapshots [get]
+func GetSnapshotsForReview(c *gin.Context) {
+	if application, ok := findReviewableApplication(c); ok {
+		respondSnapshots(c, application.ID)
+	}
+}
+
+// GetSnapshotForReview returns one submission snapshot of any application.
+//
+// @Summary Get application snapshot for review
+// @Description Returns the application data exactly as it was at one submission. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param version path int true "Snapshot version"
+// @Success 200 {object} models.SuccessResponse{data=models.ApplicationSnapshot} "Snapshot fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application or snapshot not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch snapshot"
+// @Router /review/applications/{id}/snapshots/{version} [get]
+func GetSnapshotForReview(c *gin.Context) {
+	if application, ok := findReviewableApplication(c); ok {
+		respondSnapshot(c, application.ID)
+	}
+}
+
+// DiffSnapshotsForReview compares two submissions of any application.
+//
+// @Summary Diff application snapshots for review
+// @Description Lists what changed between two submissions. Without parameters the latest submission is compared with the one before it. Reviewer or admin only.
+// @Tags Review
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param from query int false "Older snapshot version"
+// @Param to query int false "Newer snapshot version"
+// @Success 200 {object} models.SuccessResponse{data=models.SnapshotDiff} "Snapshots compared successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid application ID or versions"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "Application or snapshot not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to compare snapshots"
+// @Router /review/applications/{id}/snapshots/diff [get]
+func DiffSnapshotsForReview(c *gin.Context) {
+	if application, ok := findReviewableApplication(c); ok {
+		respondSnapshotDiff(c, application.ID)
+	}
+}
+
+// respondSnapshots writes the snapshot versions of an application, without their data, as the response.
+func respondSnapshots(c *gin.Context, applicationID uint) {
+	var snapshots []models.ApplicationSnapshot
+	if err := db.DB.
+		Omit("Data").
+		Where("application_id = ?", applicationID).
+		Order("version ASC").
+		Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch snapshots",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Snapshots fetched successfully",
+		Data:    snapshots,
+	})
+}
+
+// respondSnapshot writes the snapshot named by the version path parameter as the response.
+func respondSnapshot(c *gin.Context, applicationID uint) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Snapshot not found",
+			Error:   "invalid version " + c.Param("version"),
+		})
+		return
+	}
+
+	snapshot, ok := findSnapshot(c, applicationID, version)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Snapshot fetched successfully",
+		Data:    snapshot,
+	})
+}
+
+// respondSnapshotDiff writes the changes between the from and to snapshot versions as the response.
+// By default the latest version is compared with the one before it.
+func respondSnapshotDiff(c *gin.Context, applicationID uint) {
+	var latest int
+	if err := db.DB.Model(&models.ApplicationSnapshot{}).
+		Where("application_id = ?", applicationID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to compare snapshots",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	to, errTo := queryVersion(c, "to", latest)
+	from, errFrom := queryVersion(c, "from", to-1)
+	if err := errors.Join(errTo, errFrom); err != nil || from < 1 || to <= from {
+		message := "from must be an older version than to"
+		if err != nil {
+			message = err.Error()
+		} else if latest < 2 && c.Query("from") == "" && c.Query("to") == "" {
+			message = "the application has been submitted fewer than two times"
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid application ID or versions",
+			Error:   message,
+		})
+		return
+	}
+
+	older, ok := findSnapshot(c, applicationID, from)
+	if !ok {
+		return
+	}
+	newer, ok := findSnapshot(c, applicationID, to)
+	if !ok {
+		return
+	}
+
+	changes, err := utils.DiffJSON(older.Data, newer.Data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to compare snapshots",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Snapshots compared successfully",
+		Data: models.SnapshotDiff{
+			ApplicationID: applicationID,
+			From:          from,
+			To:            to,
+			Changes:       changes,
+		},
+	})
+}
+
+// queryVersion reads a snapshot version from a query parameter, falling back to a default.
+func queryVersion(c *gin.Context, name string, fallback int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(name + " must be a version number")
+	}
+	return version, nil
+}
+
+// findSnapshot loads one snapshot version of an application, writing a 404 or 500 response if it cannot.
+func findSnapshot(c *gin.Context, applicationID uint, version int) (*models.ApplicationSnapshot, bool) {
+	var snapshot models.ApplicationSnapshot
+	if err := db.DB.
+		Where("application_id = ? AND version = ?", applicationID, version).
+		First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Snapshot not found",
+				Error:   "no snapshot version " + strconv.Itoa(version),
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch snapshot",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	return &snapshot, true
+}
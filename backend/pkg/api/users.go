@@ -0,0 +1,579 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Register creates a new applicant account.
+//
+// @Summary Register
+// @Description Creates a new applicant account with a unique username and email.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.RegisterRequest true "Account details"
+// @Success 201 {object} models.SuccessResponse{data=models.User} "Account created successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request, username or password"
+// @Failure 409 {object} models.ErrorResponse "Username or email already registered"
+// @Failure 500 {object} models.ErrorResponse "Failed to create account"
+// @Router /auth/register [post]
+func Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := utils.CreateUser(db.DB, req.Username, req.Email, req.Password, models.RoleApplicant)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidUsername):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid username",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrWeakPassword):
+			respondPasswordError(c, err)
+		case errors.Is(err, utils.ErrUsernameTaken):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Username is already registered",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, utils.ErrEmailTaken):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Email is already registered",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			// Lost a race with a concurrent signup for the same username or email
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Username or email is already registered",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create account",
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Account created successfully",
+		Data:    user,
+	})
+}
+
+// CheckAvailability reports whether a username and/or email can still be registered.
+//
+// @Summary Check username and email availability
+// @Description Checks whether the given username and/or email are free to register.
+// @Tags Users
+// @Produce json
+// @Param username query string false "Username to check"
+// @Param email query string false "Email to check"
+// @Success 200 {object} models.SuccessResponse{data=models.AvailabilityResponse} "Availability checked"
+// @Failure 400 {object} models.ErrorResponse "Neither username nor email given"
+// @Failure 500 {object} models.ErrorResponse "Failed to check availability"
+// @Router /auth/availability [get]
+func CheckAvailability(c *gin.Context) {
+	var query models.AvailabilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil || (query.Username == "" && query.Email == "") {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Provide a username or an email to check",
+			Error:   "missing query parameters",
+		})
+		return
+	}
+
+	var res models.AvailabilityResponse
+	if query.Username != "" {
+		taken, err := utils.UsernameTaken(db.DB, query.Username)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to check availability",
+				Error:   err.Error(),
+			})
+			return
+		}
+		available := !taken && utils.ValidateUsername(query.Username) == nil
+		res.UsernameAvailable = &available
+	}
+	if query.Email != "" {
+		taken, err := utils.EmailTaken(db.DB, query.Email)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to check availability",
+				Error:   err.Error(),
+			})
+			return
+		}
+		available := !taken
+		res.EmailAvailable = &available
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Availability checked",
+		Data:    res,
+	})
+}
+
+// GetMe returns the account of the authenticated user.
+//
+// @Summary Get current account
+// @Description Returns the account details of the authenticated user.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} models.SuccessResponse{data=models.User} "Account fetched successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Failed to fetch account"
+// @Router /users/me [get]
+func GetMe(c *gin.Context) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Account fetched successfully",
+		Data:    user,
+	})
+}
+
+// DeactivateMe deactivates the account of the authenticated user.
+//
+// @Summary Deactivate current account
+// @Description Deactivates the caller's account after confirming the password. Deactivated accounts can no longer log in.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.DeactivateAccountRequest true "Password confirmation"
+// @Success 200 {object} models.SuccessResponse "Account deactivated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized or password is wrong"
+// @Failure 500 {object} models.ErrorResponse "Failed to deactivate account"
+// @Router /users/me [delete]
+func DeactivateMe(c *gin.Context) {
+	var req models.DeactivateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if match, _ := utils.VerifyPassword(user.Password, req.Password); !match {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Password is incorrect",
+			Error:   "invalid password",
+		})
+		return
+	}
+
+	if err := deactivateUser(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to deactivate account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Account deactivated successfully",
+	})
+}
+
+// DeactivateUser deactivates any user account.
+//
+// @Summary Deactivate user
+// @Description Deactivates a user account so it can no longer log in. Admin only.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.SuccessResponse "User deactivated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to deactivate user"
+// @Router /admin/users/{id} [delete]
+func DeactivateUser(c *gin.Context) {
+	id, ok := pathID(c, "user")
+	if !ok {
+		return
+	}
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := deactivateUser(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to deactivate user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "User deactivated successfully",
+		Data:    user,
+	})
+}
+
+// deactivateUser marks a user inactive and revokes their refresh tokens.
+// Applications and profiles are kept for auditing.
+func deactivateUser(user *models.User) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"is_active":      false,
+			"deactivated_at": &now,
+		}).Error; err != nil {
+			return err
+		}
+		return utils.RevokeRefreshTokens(tx, user.ID)
+	})
+}
+
+// SetUserRole changes the role of a user.
+//
+// @Summary Set user role
+// @Description Assigns the applicant, reviewer or admin role to a user and revokes their refresh tokens. Admin only.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body models.SetRoleRequest true "New role"
+// @Success 200 {object} models.SuccessResponse "User role updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid role or user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to update user role"
+// @Router /admin/users/{id}/role [put]
+func SetUserRole(c *gin.Context) {
+	var req models.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !req.Role.IsValid() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid role",
+			Error:   "unknown role " + string(req.Role),
+		})
+		return
+	}
+
+	id, ok := pathID(c, "user")
+	if !ok {
+		return
+	}
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.SetRole(db.DB, &user, req.Role); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update user role",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "User role updated successfully",
+		Data:    user,
+	})
+}
+
+// ChangePassword changes the password of the authenticated user.
+//
+// @Summary Change password
+// @Description Changes the caller's password after verifying the current one.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} models.SuccessResponse "Password changed successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request or weak password"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized or current password is wrong"
+// @Failure 500 {object} models.ErrorResponse "Failed to change password"
+// @Router /users/me/password [put]
+func ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+	userID := userIDVal.(uint)
+
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if match, _ := utils.VerifyPassword(user.Password, req.CurrentPassword); !match {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Current password is incorrect",
+			Error:   "invalid password",
+		})
+		return
+	}
+
+	if err := utils.SetPassword(db.DB, &user, req.NewPassword); err != nil {
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Password changed successfully",
+	})
+}
+
+// IssuePasswordReset creates a single-use password reset token for a user.
+//
+// @Summary Issue password reset token
+// @Description Creates a one-hour, single-use reset token for a user. The token is returned once and must be delivered to the user out of band. Admin only.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 201 {object} models.SuccessResponse{data=models.PasswordResetResponse} "Password reset token issued"
+// @Failure 400 {object} models.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized"
+// @Failure 403 {object} models.ErrorResponse "Forbidden"
+// @Failure 404 {object} models.ErrorResponse "User not found"
+// @Failure 500 {object} models.ErrorResponse "Failed to issue password reset token"
+// @Router /admin/users/{id}/password-reset [post]
+func IssuePasswordReset(c *gin.Context) {
+	id, ok := pathID(c, "user")
+	if !ok {
+		return
+	}
+	var user models.User
+	if err := db.DB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch user",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	reset, err := utils.IssuePasswordResetToken(db.DB, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to issue password reset token",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Code:    http.StatusCreated,
+		Message: "Password reset token issued",
+		Data:    reset,
+	})
+}
+
+// ResetPassword sets a new password using a password reset token.
+//
+// @Summary Reset password
+// @Description Sets a new password for the user owning a valid, unused reset token.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} models.SuccessResponse "Password reset successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid, expired or used token, or weak password"
+// @Failure 500 {object} models.ErrorResponse "Failed to reset password"
+// @Router /auth/password-reset [post]
+func ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	errInvalidToken := errors.New("invalid or expired reset token")
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordResetToken
+		if err := tx.Preload("User").
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), time.Now()).
+			First(&reset).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errInvalidToken
+			}
+			return err
+		}
+
+		if !reset.User.IsActive {
+			return errInvalidToken
+		}
+
+		if err := utils.SetPassword(tx, &reset.User, req.NewPassword); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&reset).Update("used_at", &now).Error
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidToken) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid or expired reset token",
+				Error:   err.Error(),
+			})
+			return
+		}
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Code:    http.StatusOK,
+		Message: "Password reset successfully",
+	})
+}
+
+func respondPasswordError(c *gin.Context, err error) {
+	if errors.Is(err, utils.ErrWeakPassword) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Password does not meet the password policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Failed to update password",
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestRegisterAndAvailability(t *testing.T) {
+	useTestDB(t)
+
+	register := models.RegisterRequest{Username: "asha_k", Email: "Asha@Example.org", Password: "correct horse"}
+	var user models.User
+	if w := callAPI(t, nil, http.MethodPost, "/auth/register", register, &user); w.Code != http.StatusCreated {
+		t.Fatalf("register returned %d: %s", w.Code, w.Body.String())
+	}
+	if user.Role != models.RoleApplicant || user.Email == nil || *user.Email != "asha@example.org" {
+		t.Errorf("registered user = %+v, want an applicant with a normalized email", user)
+	}
+
+	duplicates := []models.RegisterRequest{
+		{Username: "asha_k", Email: "other@example.org", Password: "correct horse"},
+		{Username: "asha_r", Email: "ASHA@example.org", Password: "correct horse"},
+	}
+	for _, req := range duplicates {
+		if w := callAPI(t, nil, http.MethodPost, "/auth/register", req, nil); w.Code != http.StatusConflict {
+			t.Errorf("registering %s <%s> again returned %d, want 409", req.Username, req.Email, w.Code)
+		}
+	}
+	weak := models.RegisterRequest{Username: "ravi", Email: "ravi@example.org", Password: "short"}
+	if w := callAPI(t, nil, http.MethodPost, "/auth/register", weak, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("weak password returned %d, want 400", w.Code)
+	}
+
+	var availability models.AvailabilityResponse
+	if w := callAPI(t, nil, http.MethodGet, "/auth/availability?username=asha_k&email=free@example.org", nil, &availability); w.Code != http.StatusOK {
+		t.Fatalf("availability returned %d", w.Code)
+	}
+	if availability.UsernameAvailable == nil || *availability.UsernameAvailable ||
+		availability.EmailAvailable == nil || !*availability.EmailAvailable {
+		t.Errorf("availability = %+v, want the username taken and the email free", availability)
+	}
+	if w := callAPI(t, nil, http.MethodGet, "/auth/availability", nil, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("availability without a query returned %d, want 400", w.Code)
+	}
+}
+
+func TestDeactivateMe(t *testing.T) {
+	useTestDB(t)
+	user := createUser(t, "asha", models.RoleApplicant)
+
+	var me models.User
+	if w := callAPI(t, user, http.MethodGet, "/users/me", nil, &me); w.Code != http.StatusOK || me.ID != user.ID {
+		t.Fatalf("GET /users/me returned %d for user %d", w.Code, me.ID)
+	}
+	if w := callAPI(t, user, http.MethodDelete, "/users/me", models.DeactivateAccountRequest{Password: "wrong password"}, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("deactivation with a wrong password returned %d, want 401", w.Code)
+	}
+	if w := callAPI(t, user, http.MethodDelete, "/users/me", models.DeactivateAccountRequest{Password: "correct horse"}, nil); w.Code != http.StatusOK {
+		t.Fatalf("deactivation returned %d", w.Code)
+	}
+	login := models.LoginRequest{Username: "asha", Password: "correct horse"}
+	if w := callAPI(t, nil, http.MethodPost, "/auth/login", login, nil); w.Code != http.StatusForbidden {
+		t.Errorf("login to a deactivated account returned %d, want 403", w.Code)
+	}
+}
+
+func TestAdminUserManagement(t *testing.T) {
+	useTestDB(t)
+	admin := createUser(t, "admin", models.RoleAdmin)
+	user := createUser(t, "ravi", models.RoleApplicant)
+	path := fmt.Sprintf("/admin/users/%d", user.ID)
+
+	if w := callAPI(t, admin, http.MethodPut, path+"/role", models.SetRoleRequest{Role: models.RoleReviewer}, nil); w.Code != http.StatusOK {
+		t.Fatalf("set role returned %d: %s", w.Code, w.Body.String())
+	}
+	var stored models.User
+	if err := db.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Role != models.RoleReviewer {
+		t.Errorf("role = %s, want reviewer", stored.Role)
+	}
+	if w := callAPI(t, admin, http.MethodPut, path+"/role", models.SetRoleRequest{Role: "superuser"}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown role returned %d, want 400", w.Code)
+	}
+
+	var reset models.PasswordResetResponse
+	if w := callAPI(t, admin, http.MethodPost, path+"/password-reset", nil, &reset); w.Code != http.StatusCreated || reset.Token == "" {
+		t.Fatalf("password reset returned %d with token %q", w.Code, reset.Token)
+	}
+	resetReq := models.ResetPasswordRequest{Token: reset.Token, NewPassword: "battery staple"}
+	if w := callAPI(t, nil, http.MethodPost, "/auth/password-reset", resetReq, nil); w.Code != http.StatusOK {
+		t.Fatalf("reset returned %d: %s", w.Code, w.Body.String())
+	}
+	if w := callAPI(t, nil, http.MethodPost, "/auth/password-reset", resetReq, nil); w.Code == http.StatusOK {
+		t.Error("a reset token was accepted twice")
+	}
+
+	if w := callAPI(t, admin, http.MethodDelete, path, nil, nil); w.Code != http.StatusOK {
+		t.Fatalf("deactivate returned %d", w.Code)
+	}
+	if err := db.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.IsActive {
+		t.Error("deactivated user is still active")
+	}
+	if w := callAPI(t, admin, http.MethodDelete, "/admin/users/999999", nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("deactivating a missing user returned %d, want 404", w.Code)
+	}
+}
+
+func TestAdminUserIDsAreParsed(t *testing.T) {
+	admin := &models.User{ID: 1, Username: "admin", Role: models.RoleAdmin}
+	for _, id := range []string{"1%20OR%201=1", "abc", "0", "-1"} {
+		path := "/admin/users/" + id
+		if w := callAPI(t, admin, http.MethodDelete, path, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("DELETE %s returned %d, want 400", path, w.Code)
+		}
+		if w := callAPI(t, admin, http.MethodPost, path+"/password-reset", nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("POST %s/password-reset returned %d, want 400", path, w.Code)
+		}
+		if w := callAPI(t, admin, http.MethodPut, path+"/role", models.SetRoleRequest{Role: models.RoleReviewer}, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s/role returned %d, want 400", path, w.Code)
+		}
+	}
+}
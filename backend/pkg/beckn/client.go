@@ -0,0 +1,120 @@
+package beckn
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Subscriber identifies this BPP on the network.
+type Subscriber struct {
+	ID          string
+	URI         string
+	UniqueKeyID string
+}
+
+// BPP is the subscriber identity used in callbacks.
+var BPP = Subscriber{ID: "beneficiary-manager"}
+
+// CallbackTimeout bounds every on_* callback request.
+const CallbackTimeout = 30 * time.Second
+
+var httpClient = &http.Client{Timeout: CallbackTimeout}
+
+// signingKey signs outbound callbacks. Callbacks are not sent without it.
+var signingKey ed25519.PrivateKey
+
+// Setup reads the BPP identity from the BPP_ID, BPP_URI and BPP_UNIQUE_KEY_ID environment
+// variables, the callback signing key from BPP_SIGNING_PRIVATE_KEY and the subscriber keys
+// from the file named by BECKN_REGISTRY_FILE.
+func Setup() {
+	if id := os.Getenv("BPP_ID"); id != "" {
+		BPP.ID = id
+	}
+	BPP.URI = os.Getenv("BPP_URI")
+	BPP.UniqueKeyID = os.Getenv("BPP_UNIQUE_KEY_ID")
+
+	if encoded := os.Getenv("BPP_SIGNING_PRIVATE_KEY"); encoded != "" {
+		key, err := ParsePrivateKey(encoded)
+		if err != nil {
+			log.Fatalf("Failed to load BPP signing key: %v", err)
+		}
+		if BPP.UniqueKeyID == "" {
+			log.Fatalf("BPP_UNIQUE_KEY_ID is required with BPP_SIGNING_PRIVATE_KEY")
+		}
+		signingKey = key
+	} else {
+		log.Printf("BPP_SIGNING_PRIVATE_KEY is not set, Beckn callbacks will not be sent")
+	}
+
+	if path := os.Getenv("BECKN_REGISTRY_FILE"); path != "" {
+		registry, err := LoadKeyRegistry(path)
+		if err != nil {
+			log.Fatalf("Failed to load Beckn key registry: %v", err)
+		}
+		Registry = registry
+	} else {
+		log.Printf("BECKN_REGISTRY_FILE is not set, Beckn requests will be rejected")
+	}
+}
+
+// CallbackContext derives the context of an on_* callback from the request context.
+// The transaction and message IDs are kept so the BAP can correlate the response.
+func CallbackContext(req Context) Context {
+	callback := req
+	callback.Action = "on_" + req.Action
+	callback.Version = Version
+	if callback.Domain == "" {
+		callback.Domain = Domain
+	}
+	callback.BppID = BPP.ID
+	callback.BppURI = BPP.URI
+	callback.Timestamp = time.Now().UTC()
+	return callback
+}
+
+// SendCallback signs an on_* callback and posts it to the BAP that sent the request.
+//
+// Parameters:
+// - ctx (context.Context): Bounds the request.
+// - callback (Callback): The callback; its context must come from CallbackContext.
+//
+// Returns:
+// - error: An error if no signing key is configured, the request fails or the BAP does not acknowledge it.
+func SendCallback(ctx context.Context, callback Callback) error {
+	if signingKey == nil {
+		return fmt.Errorf("cannot send %s: no signing key configured", callback.Context.Action)
+	}
+
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return fmt.Errorf("failed to encode callback: %w", err)
+	}
+
+	url := strings.TrimSuffix(callback.Context.BapURI, "/") + "/" + callback.Context.Action
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Digest", Digest(body))
+	req.Header.Set("Authorization", Sign(body, BPP.ID, BPP.UniqueKeyID, signingKey, time.Now()).String())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s: %w", callback.Context.Action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s rejected by %s: %s", callback.Context.Action, callback.Context.BapID, resp.Status)
+	}
+	return nil
+}
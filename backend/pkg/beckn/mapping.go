@@ -0,0 +1,196 @@
+package beckn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+// Currency of all scheme amounts.
+const Currency = "INR"
+
+// Person tags that map onto student profile fields.
+const (
+	TagCategory      = "category"
+	TagIncome        = "income"
+	TagQualification = "qualification"
+	TagAadhaar       = "aadhaar_number"
+	TagNationality   = "nationality"
+)
+
+// SchemeItem maps a scheme onto a catalog item. Eligibility criteria and required
+// documents are exposed as tag groups so seekers can pre-screen applicants.
+func SchemeItem(scheme *models.Scheme) Item {
+	item := Item{
+		ID: strconv.FormatUint(uint64(scheme.ID), 10),
+		Descriptor: &Descriptor{
+			Code:      scheme.Status,
+			Name:      scheme.Name,
+			ShortDesc: scheme.Description,
+		},
+		Price: SchemePrice(scheme),
+		Time:  &Time{Range: &TimeRange{Start: scheme.StartDate, End: scheme.EndDate}},
+	}
+
+	e := scheme.Eligibility
+	var criteria []Tag
+	addCriterion := func(code, value string) {
+		if value != "" {
+			criteria = append(criteria, Tag{Descriptor: Descriptor{Code: code}, Value: value})
+		}
+	}
+	addCriterion("gender", string(e.Gender))
+	if e.AgeMin > 0 {
+		addCriterion("age_min", strconv.Itoa(e.AgeMin))
+	}
+	if e.AgeMax > 0 {
+		addCriterion("age_max", strconv.Itoa(e.AgeMax))
+	}
+	if e.IncomeLimit > 0 {
+		addCriterion("income_limit", strconv.FormatFloat(e.IncomeLimit, 'f', -1, 64))
+	}
+	addCriterion("academic_qualification", string(e.AcademicQualification))
+	addCriterion("category", string(e.Category))
+	if len(criteria) > 0 {
+		item.Tags = append(item.Tags, TagGroup{
+			Descriptor: Descriptor{Code: "eligibility", Name: "Eligibility"},
+			List:       criteria,
+		})
+	}
+
+	var documents []Tag
+	for _, mapping := range e.DocumentMappings {
+		documents = append(documents, Tag{
+			Descriptor: Descriptor{Code: mapping.Document.Name, Name: mapping.Document.Description},
+			Value:      strconv.FormatBool(mapping.IsMandatory),
+		})
+	}
+	if len(documents) > 0 {
+		item.Tags = append(item.Tags, TagGroup{
+			Descriptor: Descriptor{Code: "required_documents", Name: "Required documents"},
+			List:       documents,
+		})
+	}
+	return item
+}
+
+// SchemePrice returns the benefit amount of a scheme.
+func SchemePrice(scheme *models.Scheme) *Price {
+	return &Price{Currency: Currency, Value: strconv.FormatFloat(scheme.Amount, 'f', 2, 64)}
+}
+
+// ParseID parses a numeric item or order ID.
+func ParseID(id string) (uint, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return uint(n), nil
+}
+
+// CustomerIdentity returns the value that identifies a customer within a BAP.
+func CustomerIdentity(customer *Customer) string {
+	if email := strings.ToLower(strings.TrimSpace(customer.Contact.Email)); email != "" {
+		return email
+	}
+	return strings.TrimSpace(customer.Contact.Phone)
+}
+
+// CustomerProfile maps a customer onto a student profile and the documents it references.
+func CustomerProfile(customer *Customer) (*models.StudentProfile, []models.DocumentInput, error) {
+	profile := &models.StudentProfile{
+		FullName:    customer.Person.Name,
+		Gender:      customer.Person.Gender,
+		PhoneNumber: customer.Contact.Phone,
+		Email:       customer.Contact.Email,
+	}
+	if profile.FullName == "" {
+		profile.FullName = "Unknown"
+	}
+	if customer.Person.Dob != "" {
+		dob, err := time.Parse(time.DateOnly, customer.Person.Dob)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid dob %q: expected YYYY-MM-DD", customer.Person.Dob)
+		}
+		profile.DateOfBirth = &dob
+	}
+
+	for _, group := range customer.Person.Tags {
+		for _, tag := range group.List {
+			switch tag.Descriptor.Code {
+			case TagCategory:
+				profile.Category = tag.Value
+			case TagQualification:
+				profile.Qualification = tag.Value
+			case TagAadhaar:
+				profile.AadhaarNumber = tag.Value
+			case TagNationality:
+				profile.Nationality = tag.Value
+			case TagIncome:
+				income, err := strconv.ParseFloat(tag.Value, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid income %q", tag.Value)
+				}
+				profile.Income = income
+			}
+		}
+	}
+
+	var documents []models.DocumentInput
+	for _, cred := range customer.Person.Creds {
+		documents = append(documents, models.DocumentInput{Name: cred.Type, URL: cred.URL})
+	}
+	return profile, documents, nil
+}
+
+// OrderStatus maps an application status onto a Beckn order status.
+func OrderStatus(status models.ApplicationStatus) string {
+	switch status {
+	case models.ApplicationStatusDraft, "":
+		return "CREATED"
+	case models.ApplicationStatusApproved, models.ApplicationStatusRejected:
+		return "COMPLETE"
+	case models.ApplicationStatusWithdrawn:
+		return "CANCELLED"
+	default:
+		return "ACTIVE"
+	}
+}
+
+// ApplicationOrder maps an application onto an order. The fulfillment state carries the
+// detailed application status.
+func ApplicationOrder(app *models.Application) Order {
+	state := &State{
+		Descriptor: Descriptor{Code: string(app.Status), ShortDesc: app.StatusReason},
+		UpdatedAt:  app.UpdatedAt,
+	}
+	if app.Status == "" {
+		state.Descriptor.Code = string(models.ApplicationStatusDraft)
+	}
+
+	item := Item{ID: strconv.FormatUint(uint64(app.SchemeID), 10)}
+	order := Order{
+		ID:           strconv.FormatUint(uint64(app.ID), 10),
+		Status:       OrderStatus(app.Status),
+		Provider:     &Provider{ID: BPP.ID},
+		Fulfillments: []Fulfillment{{ID: strconv.FormatUint(uint64(app.ID), 10), State: state}},
+	}
+	if app.Scheme.ID != 0 {
+		item = SchemeItem(&app.Scheme)
+		order.Quote = &Quotation{Price: *SchemePrice(&app.Scheme)}
+	}
+	order.Items = []Item{item}
+	return order
+}
+
+// ApplicantUsername derives the username of the shadow account that holds the
+// applications of one customer of one BAP.
+func ApplicantUsername(bapID, identity string) string {
+	sum := sha256.Sum256([]byte(bapID + "|" + identity))
+	return "beckn-" + hex.EncodeToString(sum[:])[:24]
+}
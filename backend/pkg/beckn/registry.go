@@ -0,0 +1,120 @@
+package beckn
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrUnknownKey is returned when the registry holds no valid key for a subscriber.
+var ErrUnknownKey = errors.New("unknown subscriber key")
+
+// RegistryEntry is a subscriber signing key as listed in the registry file.
+type RegistryEntry struct {
+	SubscriberID     string     `json:"subscriber_id"`
+	SubscriberURL    string     `json:"subscriber_url"` // the only bap_uri callbacks to this subscriber are sent to
+	UniqueKeyID      string     `json:"unique_key_id"`
+	SigningPublicKey string     `json:"signing_public_key"` // base64 Ed25519 public key
+	ValidFrom        *time.Time `json:"valid_from,omitempty"`
+	ValidUntil       *time.Time `json:"valid_until,omitempty"`
+}
+
+type registryKey struct {
+	key        ed25519.PublicKey
+	validFrom  *time.Time
+	validUntil *time.Time
+}
+
+// KeyRegistry resolves subscriber public keys. It is loaded from a local file so the
+// network registry lookup can be replaced by a static list, e.g. for offline testing.
+type KeyRegistry struct {
+	keys map[string]registryKey
+	urls map[string]string
+}
+
+// Registry holds the keys of the network participants allowed to call this BPP.
+var Registry = &KeyRegistry{keys: map[string]registryKey{}, urls: map[string]string{}}
+
+// NewKeyRegistry builds a registry from a list of entries. Every entry needs the subscriber URL,
+// and all keys of a subscriber must list the same one.
+func NewKeyRegistry(entries []RegistryEntry) (*KeyRegistry, error) {
+	registry := &KeyRegistry{keys: make(map[string]registryKey, len(entries)), urls: make(map[string]string)}
+	for _, entry := range entries {
+		raw, err := base64.StdEncoding.DecodeString(entry.SigningPublicKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key for %s|%s", entry.SubscriberID, entry.UniqueKeyID)
+		}
+		subscriberURL, err := normalizeSubscriberURL(entry.SubscriberURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subscriber_url for %s|%s: %w", entry.SubscriberID, entry.UniqueKeyID, err)
+		}
+		if known, ok := registry.urls[entry.SubscriberID]; ok && known != subscriberURL {
+			return nil, fmt.Errorf("conflicting subscriber_url for %s", entry.SubscriberID)
+		}
+		registry.urls[entry.SubscriberID] = subscriberURL
+		registry.keys[entry.SubscriberID+"|"+entry.UniqueKeyID] = registryKey{
+			key:        ed25519.PublicKey(raw),
+			validFrom:  entry.ValidFrom,
+			validUntil: entry.ValidUntil,
+		}
+	}
+	return registry, nil
+}
+
+// LoadKeyRegistry reads a JSON array of RegistryEntry from a file.
+func LoadKeyRegistry(path string) (*KeyRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key registry: %w", err)
+	}
+	var entries []RegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse key registry: %w", err)
+	}
+	return NewKeyRegistry(entries)
+}
+
+// MatchesSubscriberURL reports whether a callback URL is the registered URL of a subscriber.
+// Only registered URLs are called back, so a signed request cannot point this service at
+// arbitrary hosts.
+func (r *KeyRegistry) MatchesSubscriberURL(subscriberID, callbackURL string) bool {
+	registered, ok := r.urls[subscriberID]
+	if !ok {
+		return false
+	}
+	normalized, err := normalizeSubscriberURL(callbackURL)
+	return err == nil && normalized == registered
+}
+
+// normalizeSubscriberURL checks that a subscriber URL is an absolute http(s) URL and strips the
+// trailing slash, which callbacks add back before the action.
+func normalizeSubscriberURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", errors.New("must be an absolute http or https URL without credentials, query or fragment")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// PublicKey returns the key registered for a subscriber key ID if it is valid at the given time.
+func (r *KeyRegistry) PublicKey(subscriberID, uniqueKeyID string, at time.Time) (ed25519.PublicKey, error) {
+	entry, ok := r.keys[subscriberID+"|"+uniqueKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s|%s", ErrUnknownKey, subscriberID, uniqueKeyID)
+	}
+	if (entry.validFrom != nil && at.Before(*entry.validFrom)) || (entry.validUntil != nil && at.After(*entry.validUntil)) {
+		return nil, fmt.Errorf("%w: %s|%s is not valid at this time", ErrUnknownKey, subscriberID, uniqueKeyID)
+	}
+	return entry.key, nil
+}
@@ -0,0 +1,54 @@
+package beckn
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache remembers the message IDs of accepted requests while their signatures are valid,
+// so a captured request cannot be sent again.
+type ReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Replays holds the messages accepted by this BPP.
+var Replays = NewReplayCache()
+
+// NewReplayCache returns an empty cache.
+func NewReplayCache() *ReplayCache {
+	return &ReplayCache{seen: make(map[string]time.Time)}
+}
+
+// Seen records a message and reports whether the subscriber already sent a message with the same
+// ID. The message is remembered for SignatureTTL, or until its signature expires if that is later.
+//
+// Parameters:
+// - subscriberID (string): The verified signer of the request.
+// - messageID (string): The message_id of the request context.
+// - expires (time.Time): The expiry of the request signature.
+// - now (time.Time): The time the request was received.
+//
+// Returns:
+// - bool: Whether the message is a replay.
+func (r *ReplayCache) Seen(subscriberID, messageID string, expires, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, until := range r.seen {
+		if now.After(until) {
+			delete(r.seen, key)
+		}
+	}
+
+	key := subscriberID + "|" + messageID
+	if _, ok := r.seen[key]; ok {
+		return true
+	}
+	until := now.Add(SignatureTTL)
+	if late := expires.Add(signatureClockSkew); late.After(until) {
+		until = late
+	}
+	r.seen[key] = until
+	return false
+}
@@ -0,0 +1,42 @@
+package beckn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayCache(t *testing.T) {
+	cache := NewReplayCache()
+	now := time.Unix(1700000000, 0)
+	expires := now.Add(SignatureTTL)
+
+	if cache.Seen("bap.example.com", "msg-1", expires, now) {
+		t.Fatal("first message reported as a replay")
+	}
+	if !cache.Seen("bap.example.com", "msg-1", expires, now.Add(time.Minute)) {
+		t.Error("repeated message not reported as a replay")
+	}
+	if cache.Seen("other.example.com", "msg-1", expires, now) {
+		t.Error("same message ID from another subscriber reported as a replay")
+	}
+	if cache.Seen("bap.example.com", "msg-2", expires, now) {
+		t.Error("other message ID reported as a replay")
+	}
+
+	// The message is forgotten once its signature can no longer verify
+	later := expires.Add(signatureClockSkew + time.Second)
+	if cache.Seen("bap.example.com", "msg-1", later.Add(SignatureTTL), later) {
+		t.Error("message still remembered after its signature expired")
+	}
+}
+
+func TestReplayCacheKeepsUntilSignatureExpiry(t *testing.T) {
+	cache := NewReplayCache()
+	now := time.Unix(1700000000, 0)
+	expires := now.Add(time.Hour)
+
+	cache.Seen("bap.example.com", "msg-1", expires, now)
+	if !cache.Seen("bap.example.com", "msg-1", expires, now.Add(30*time.Minute)) {
+		t.Error("message forgotten while its signature is still valid")
+	}
+}
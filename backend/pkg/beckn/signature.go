@@ -0,0 +1,181 @@
+package beckn
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// SignatureAlgorithm is the only signing algorithm accepted on the network.
+const SignatureAlgorithm = "ed25519"
+
+// SignatureTTL is the validity window of signatures created by this service.
+const SignatureTTL = 5 * time.Minute
+
+// signatureClockSkew tolerates small clock differences between participants.
+const signatureClockSkew = 30 * time.Second
+
+const signedHeaders = "(created) (expires) digest"
+
+// ErrInvalidSignature is returned when a request signature is missing, malformed or does not verify.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+var signatureParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Signature is a parsed Authorization header of the form
+//
+//	Signature keyId="{subscriber_id}|{unique_key_id}|ed25519",algorithm="ed25519",
+//	created="…",expires="…",headers="(created) (expires) digest",signature="…"
+type Signature struct {
+	SubscriberID string
+	UniqueKeyID  string
+	Created      int64
+	Expires      int64
+	Signature    []byte
+}
+
+// Digest returns the BLAKE2b-512 digest of a request body in the form used by the signing string.
+func Digest(body []byte) string {
+	sum := blake2b.Sum512(body)
+	return "BLAKE-512=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func signingString(created, expires int64, body []byte) string {
+	return fmt.Sprintf("(created): %d\n(expires): %d\ndigest: %s", created, expires, Digest(body))
+}
+
+// String encodes the signature as an Authorization header value.
+func (s *Signature) String() string {
+	return fmt.Sprintf(`Signature keyId="%s|%s|%s",algorithm="%s",created="%d",expires="%d",headers="%s",signature="%s"`,
+		s.SubscriberID, s.UniqueKeyID, SignatureAlgorithm, SignatureAlgorithm,
+		s.Created, s.Expires, signedHeaders, base64.StdEncoding.EncodeToString(s.Signature))
+}
+
+// Sign signs a request body on behalf of a subscriber key.
+//
+// Parameters:
+// - body ([]byte): The exact request body that will be sent.
+// - subscriberID (string): The subscriber ID of the signer.
+// - uniqueKeyID (string): The ID of the signing key in the registry.
+// - key (ed25519.PrivateKey): The signing key.
+// - now (time.Time): The creation time of the signature.
+//
+// Returns:
+// - *Signature: The signature, to be sent with String() as the Authorization header.
+func Sign(body []byte, subscriberID, uniqueKeyID string, key ed25519.PrivateKey, now time.Time) *Signature {
+	created := now.Unix()
+	expires := now.Add(SignatureTTL).Unix()
+	return &Signature{
+		SubscriberID: subscriberID,
+		UniqueKeyID:  uniqueKeyID,
+		Created:      created,
+		Expires:      expires,
+		Signature:    ed25519.Sign(key, []byte(signingString(created, expires, body))),
+	}
+}
+
+// ParseSignature parses an Authorization header created by Signature.String.
+func ParseSignature(header string) (*Signature, error) {
+	params, ok := strings.CutPrefix(header, "Signature ")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing Signature authorization", ErrInvalidSignature)
+	}
+
+	values := make(map[string]string)
+	for _, match := range signatureParamPattern.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+
+	keyID := strings.Split(values["keyId"], "|")
+	if len(keyID) != 3 || keyID[0] == "" || keyID[1] == "" {
+		return nil, fmt.Errorf("%w: malformed keyId", ErrInvalidSignature)
+	}
+	if keyID[2] != SignatureAlgorithm || values["algorithm"] != SignatureAlgorithm {
+		return nil, fmt.Errorf("%w: unsupported algorithm", ErrInvalidSignature)
+	}
+	if values["headers"] != signedHeaders {
+		return nil, fmt.Errorf("%w: signed headers must be %q", ErrInvalidSignature, signedHeaders)
+	}
+
+	created, err := strconv.ParseInt(values["created"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed created", ErrInvalidSignature)
+	}
+	expires, err := strconv.ParseInt(values["expires"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed expires", ErrInvalidSignature)
+	}
+	signature, err := base64.StdEncoding.DecodeString(values["signature"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+
+	return &Signature{
+		SubscriberID: keyID[0],
+		UniqueKeyID:  keyID[1],
+		Created:      created,
+		Expires:      expires,
+		Signature:    signature,
+	}, nil
+}
+
+// VerifySignature checks an Authorization header against a request body and the key registry.
+//
+// Parameters:
+// - header (string): The Authorization header value.
+// - body ([]byte): The raw request body.
+// - registry (*KeyRegistry): The registry holding the public key of the signer.
+// - now (time.Time): The time the request was received.
+//
+// Returns:
+// - *Signature: The verified signature, identifying the signer.
+// - error: An error wrapping ErrInvalidSignature if verification fails.
+func VerifySignature(header string, body []byte, registry *KeyRegistry, now time.Time) (*Signature, error) {
+	sig, err := ParseSignature(header)
+	if err != nil {
+		return nil, err
+	}
+
+	created := time.Unix(sig.Created, 0)
+	expires := time.Unix(sig.Expires, 0)
+	if created.After(now.Add(signatureClockSkew)) || expires.Before(now.Add(-signatureClockSkew)) {
+		return nil, fmt.Errorf("%w: signature is not valid at this time", ErrInvalidSignature)
+	}
+
+	key, err := registry.PublicKey(sig.SubscriberID, sig.UniqueKeyID, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !ed25519.Verify(key, []byte(signingString(sig.Created, sig.Expires, body)), sig.Signature) {
+		return nil, fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	return sig, nil
+}
+
+// Challenge returns the WWW-Authenticate header value sent with rejected requests.
+func Challenge() string {
+	return fmt.Sprintf(`Signature realm="%s",headers="%s"`, BPP.ID, signedHeaders)
+}
+
+// ParsePrivateKey decodes a base64 Ed25519 private key given either as a 32-byte seed or a 64-byte key.
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key encoding: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid private key length %d", len(raw))
+	}
+}
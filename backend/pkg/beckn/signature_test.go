@@ -0,0 +1,160 @@
+package beckn
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSubscriberURL = "https://bap.example.com/beckn"
+
+// testRegistry returns a signing key and a registry holding its public key as bap.example.com|key-1.
+func testRegistry(t *testing.T) (ed25519.PrivateKey, *KeyRegistry) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := NewKeyRegistry([]RegistryEntry{{
+		SubscriberID:     "bap.example.com",
+		SubscriberURL:    testSubscriberURL,
+		UniqueKeyID:      "key-1",
+		SigningPublicKey: base64.StdEncoding.EncodeToString(public),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private, registry
+}
+
+func TestSignatureRoundTrip(t *testing.T) {
+	key, registry := testRegistry(t)
+	body := []byte(`{"context":{"action":"search"}}`)
+	now := time.Unix(1700000000, 0)
+
+	header := Sign(body, "bap.example.com", "key-1", key, now).String()
+	sig, err := VerifySignature(header, body, registry, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("VerifySignature() error = %v", err)
+	}
+	if sig.SubscriberID != "bap.example.com" || sig.UniqueKeyID != "key-1" {
+		t.Errorf("signer = %s|%s, want bap.example.com|key-1", sig.SubscriberID, sig.UniqueKeyID)
+	}
+	if sig.Expires-sig.Created != int64(SignatureTTL/time.Second) {
+		t.Errorf("signature valid for %ds, want %v", sig.Expires-sig.Created, SignatureTTL)
+	}
+}
+
+func TestVerifySignatureRejects(t *testing.T) {
+	key, registry := testRegistry(t)
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"context":{"action":"search"}}`)
+	now := time.Unix(1700000000, 0)
+	valid := Sign(body, "bap.example.com", "key-1", key, now).String()
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		at     time.Time
+	}{
+		{"expired", valid, body, now.Add(SignatureTTL + signatureClockSkew + time.Second)},
+		{"not yet valid", valid, body, now.Add(-signatureClockSkew - time.Second)},
+		{"tampered body", valid, []byte(`{"context":{"action":"select"}}`), now},
+		{"unknown key ID", Sign(body, "bap.example.com", "key-2", key, now).String(), body, now},
+		{"unknown subscriber", Sign(body, "other.example.com", "key-1", key, now).String(), body, now},
+		{"wrong key", Sign(body, "bap.example.com", "key-1", otherKey, now).String(), body, now},
+		{"missing header", "", body, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifySignature(tt.header, tt.body, registry, tt.at); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifySignature() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureClockSkew(t *testing.T) {
+	key, registry := testRegistry(t)
+	body := []byte(`{}`)
+	now := time.Unix(1700000000, 0)
+	header := Sign(body, "bap.example.com", "key-1", key, now).String()
+
+	for _, at := range []time.Time{now.Add(-signatureClockSkew), now.Add(SignatureTTL + signatureClockSkew)} {
+		if _, err := VerifySignature(header, body, registry, at); err != nil {
+			t.Errorf("VerifySignature() at %v error = %v", at.Sub(now), err)
+		}
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	signature := base64.StdEncoding.EncodeToString([]byte("sig"))
+	header := func(replace ...string) string {
+		h := `Signature keyId="bap.example.com|key-1|ed25519",algorithm="ed25519",created="1700000000",` +
+			`expires="1700000300",headers="(created) (expires) digest",signature="` + signature + `"`
+		return strings.NewReplacer(replace...).Replace(h)
+	}
+
+	sig, err := ParseSignature(header())
+	if err != nil {
+		t.Fatalf("ParseSignature() error = %v", err)
+	}
+	if sig.SubscriberID != "bap.example.com" || sig.UniqueKeyID != "key-1" ||
+		sig.Created != 1700000000 || sig.Expires != 1700000300 || string(sig.Signature) != "sig" {
+		t.Errorf("ParseSignature() = %+v", sig)
+	}
+	if again, err := ParseSignature(sig.String()); err != nil || again.SubscriberID != sig.SubscriberID || again.Expires != sig.Expires {
+		t.Errorf("ParseSignature(String()) = %+v, %v", again, err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"not a signature", "Bearer token"},
+		{"missing keyId", header(`keyId="bap.example.com|key-1|ed25519",`, "")},
+		{"short keyId", header("bap.example.com|key-1|ed25519", "bap.example.com|ed25519")},
+		{"empty subscriber", header("bap.example.com|key-1|", "|key-1|")},
+		{"other algorithm in keyId", header("key-1|ed25519", "key-1|rsa")},
+		{"other algorithm", header(`algorithm="ed25519"`, `algorithm="rsa"`)},
+		{"other headers", header("(created) (expires) digest", "digest")},
+		{"malformed created", header(`created="1700000000"`, `created="soon"`)},
+		{"malformed expires", header(`expires="1700000300"`, `expires=""`)},
+		{"malformed signature", header(signature, "not base64!")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSignature(tt.header); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("ParseSignature() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString(key.Seed()),
+		base64.StdEncoding.EncodeToString(key) + "\n",
+	} {
+		parsed, err := ParsePrivateKey(encoded)
+		if err != nil || !parsed.Equal(key) {
+			t.Errorf("ParsePrivateKey(%q) = %v, want the generated key", encoded, err)
+		}
+	}
+	for _, encoded := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParsePrivateKey(encoded); err == nil {
+			t.Errorf("ParsePrivateKey(%q) succeeded", encoded)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package beckn implements the subset of the Beckn protocol (v1.1) needed to expose
+// the scheme catalog as a BPP (provider platform) on the ONEST network.
+package beckn
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Protocol constants.
+const (
+	Version = "1.1.0"
+	Domain  = "onest:financial-support"
+)
+
+// Actions handled by the BPP. Callbacks use the same name prefixed with "on_".
+const (
+	ActionSearch  = "search"
+	ActionSelect  = "select"
+	ActionInit    = "init"
+	ActionConfirm = "confirm"
+	ActionStatus  = "status"
+	ActionCancel  = "cancel"
+)
+
+// Error codes returned in callbacks.
+const (
+	ErrCodeInvalidRequest   = "30000"
+	ErrCodeItemNotFound     = "30004"
+	ErrCodeOrderNotFound    = "30005"
+	ErrCodeNotEligible      = "30006"
+	ErrCodeNotCancellable   = "30009"
+	ErrCodeInvalidSignature = "30016"
+	ErrCodeInternal         = "31001"
+	ErrCodeItemUnavailable  = "40002"
+)
+
+// Context carries the routing and correlation data of every Beckn message.
+type Context struct {
+	Domain        string    `json:"domain"`
+	Action        string    `json:"action"`
+	Version       string    `json:"version"`
+	BapID         string    `json:"bap_id"`
+	BapURI        string    `json:"bap_uri"`
+	BppID         string    `json:"bpp_id,omitempty"`
+	BppURI        string    `json:"bpp_uri,omitempty"`
+	TransactionID string    `json:"transaction_id"`
+	MessageID     string    `json:"message_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	TTL           string    `json:"ttl,omitempty"`
+}
+
+// Request is the envelope of every incoming BAP request. Message is decoded per action.
+type Request struct {
+	Context Context         `json:"context"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Callback is the envelope of every on_* callback sent to the BAP.
+type Callback struct {
+	Context Context     `json:"context"`
+	Message interface{} `json:"message,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+}
+
+// Ack is the status of a synchronous acknowledgement.
+type Ack struct {
+	Status string `json:"status"`
+}
+
+// AckMessage wraps an Ack in a response message.
+type AckMessage struct {
+	Ack Ack `json:"ack"`
+}
+
+// AckResponse is returned synchronously for every request before it is processed.
+type AckResponse struct {
+	Message AckMessage `json:"message"`
+	Error   *Error     `json:"error,omitempty"`
+}
+
+// Error describes why a request could not be fulfilled.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewAck builds an ACK response.
+func NewAck() AckResponse {
+	return AckResponse{Message: AckMessage{Ack: Ack{Status: "ACK"}}}
+}
+
+// NewNack builds a NACK response carrying an error.
+func NewNack(code, message string) AckResponse {
+	return AckResponse{
+		Message: AckMessage{Ack: Ack{Status: "NACK"}},
+		Error:   &Error{Code: code, Message: message},
+	}
+}
+
+// ------------------ Catalog ------------------
+
+// Descriptor describes any catalog entity.
+type Descriptor struct {
+	Code      string `json:"code,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ShortDesc string `json:"short_desc,omitempty"`
+	LongDesc  string `json:"long_desc,omitempty"`
+}
+
+// Tag is a single code/value pair.
+type Tag struct {
+	Descriptor Descriptor `json:"descriptor"`
+	Value      string     `json:"value"`
+}
+
+// TagGroup groups related tags under a common descriptor.
+type TagGroup struct {
+	Descriptor Descriptor `json:"descriptor"`
+	List       []Tag      `json:"list"`
+}
+
+// Price is a monetary amount.
+type Price struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+// TimeRange is a start/end interval.
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Time describes when an entity is available.
+type Time struct {
+	Range *TimeRange `json:"range,omitempty"`
+}
+
+// Item is a scheme offered by the provider.
+type Item struct {
+	ID         string      `json:"id"`
+	Descriptor *Descriptor `json:"descriptor,omitempty"`
+	Price      *Price      `json:"price,omitempty"`
+	Time       *Time       `json:"time,omitempty"`
+	Tags       []TagGroup  `json:"tags,omitempty"`
+}
+
+// Provider groups the items offered by this BPP.
+type Provider struct {
+	ID         string      `json:"id"`
+	Descriptor *Descriptor `json:"descriptor,omitempty"`
+	Items      []Item      `json:"items,omitempty"`
+}
+
+// Catalog is the result of a search.
+type Catalog struct {
+	Descriptor Descriptor `json:"descriptor"`
+	Providers  []Provider `json:"providers"`
+}
+
+// ------------------ Orders ------------------
+
+// Credential is a document held by the applicant.
+type Credential struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Person holds the personal details of an applicant.
+type Person struct {
+	Name   string       `json:"name,omitempty"`
+	Gender string       `json:"gender,omitempty"`
+	Dob    string       `json:"dob,omitempty"` // YYYY-MM-DD
+	Creds  []Credential `json:"creds,omitempty"`
+	Tags   []TagGroup   `json:"tags,omitempty"`
+}
+
+// Contact holds the contact details of an applicant.
+type Contact struct {
+	Phone string `json:"phone,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// Customer is the applicant of an order.
+type Customer struct {
+	Person  Person  `json:"person"`
+	Contact Contact `json:"contact"`
+}
+
+// State is the state of a fulfillment.
+type State struct {
+	Descriptor Descriptor `json:"descriptor"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+}
+
+// Fulfillment carries the applicant and the progress of an application.
+type Fulfillment struct {
+	ID       string    `json:"id,omitempty"`
+	State    *State    `json:"state,omitempty"`
+	Customer *Customer `json:"customer,omitempty"`
+}
+
+// Quotation is the benefit amount of an order.
+type Quotation struct {
+	Price Price `json:"price"`
+}
+
+// Order is an application for a scheme.
+type Order struct {
+	ID           string        `json:"id,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Provider     *Provider     `json:"provider,omitempty"`
+	Items        []Item        `json:"items"`
+	Fulfillments []Fulfillment `json:"fulfillments,omitempty"`
+	Quote        *Quotation    `json:"quote,omitempty"`
+}
+
+// ------------------ Messages ------------------
+
+// Intent describes what a seeker is searching for.
+type Intent struct {
+	Item     *Item     `json:"item,omitempty"`
+	Provider *Provider `json:"provider,omitempty"`
+}
+
+// SearchMessage is the message of a search request.
+type SearchMessage struct {
+	Intent Intent `json:"intent"`
+}
+
+// CatalogMessage is the message of an on_search callback.
+type CatalogMessage struct {
+	Catalog Catalog `json:"catalog"`
+}
+
+// OrderMessage is the message of select, init and confirm requests and of all order callbacks.
+type OrderMessage struct {
+	Order Order `json:"order"`
+}
+
+// StatusMessage is the message of a status request.
+type StatusMessage struct {
+	OrderID string `json:"order_id"`
+}
+
+// CancelMessage is the message of a cancel request.
+type CancelMessage struct {
+	OrderID              string      `json:"order_id"`
+	CancellationReasonID string      `json:"cancellation_reason_id,omitempty"`
+	Descriptor           *Descriptor `json:"descriptor,omitempty"`
+}
@@ -0,0 +1,66 @@
+// Package dbtest gives tests a throwaway PostgreSQL schema.
+//
+// Tests that need a database are skipped unless TEST_DATABASE_DSN holds a keyword/value
+// connection string, e.g. "host=localhost port=5432 user=postgres password=postgres dbname=postgres".
+// Every call creates a fresh schema that is dropped when the test ends.
+package dbtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/migrations"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open returns a connection to an empty schema, skipping the test if no database is configured.
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + hex.EncodeToString(buf)
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	conn, err := gorm.Open(postgres.Open(dsn+" search_path="+schema),
+		&gorm.Config{TranslateError: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to connect to test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return conn
+}
+
+// Migrated returns a connection to a schema with every migration applied.
+func Migrated(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn := Open(t)
+	if _, err := migrations.Up(conn); err != nil {
+		t.Fatalf("failed to migrate test schema: %v", err)
+	}
+	return conn
+}
@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ChayanDass/beneficiary-manager/pkg/beckn"
 	"github.com/ChayanDass/beneficiary-manager/pkg/db"
 	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // CORSMiddleware is a middleware function for CORS.
@@ -29,6 +34,55 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// Authenticate is a middleware that accepts either a Bearer access token or Basic credentials
+// and populates the same user context keys for both.
+func Authenticate() gin.HandlerFunc {
+	basic := BasicAuth()
+	bearer := JWTAuth()
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.GetHeader("Authorization"), "Bearer ") {
+			bearer(c)
+			return
+		}
+		basic(c)
+	}
+}
+
+// JWTAuth is a middleware that verifies a Bearer access token without a database lookup.
+func JWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Missing or invalid Authorization header",
+			})
+			return
+		}
+
+		claims, err := utils.ParseAccessToken(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired access token",
+			})
+			return
+		}
+		userID, err := claims.UserID()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired access token",
+			})
+			return
+		}
+
+		// Set user context
+		c.Set("username", claims.Username)
+		c.Set("user_id", userID)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// BasicAuth is a middleware that authenticates a user with HTTP Basic credentials.
 func BasicAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
@@ -61,13 +115,22 @@ func BasicAuth() gin.HandlerFunc {
 		username, password := parts[0], parts[1]
 
 		// Check user
-		var user models.User
-		if err := db.DB.Where("username = ?", username).First(&user).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
+		user, err := utils.AuthenticateUser(db.DB, username, password)
+		if err != nil {
+			switch {
+			case errors.Is(err, utils.ErrUnknownUser):
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 					"error": "Invalid username",
 				})
-			} else {
+			case errors.Is(err, utils.ErrWrongPassword):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid password",
+				})
+			case errors.Is(err, utils.ErrAccountDeactivated):
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error": "Account is deactivated",
+				})
+			default:
 				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 					"error": "Database error",
 				})
@@ -75,17 +138,70 @@ func BasicAuth() gin.HandlerFunc {
 			return
 		}
 
-		// You should verify password here with bcrypt or plain text
-		if user.Password != password {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid password",
+		// Set user context
+		c.Set("username", username)
+		c.Set("user_id", user.ID)
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
+
+// RequirePermission is a middleware that guards a route group by role: only users whose role
+// grants the given permission are let through.
+// It must run after an authentication middleware that sets the "role" context key.
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		r, ok := role.(models.Role)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "Role not found in context",
+				Error:   "unauthorized",
 			})
 			return
 		}
 
-		// Set user context
-		c.Set("username", username)
-		c.Set("user_id", user.ID)
+		if !r.HasPermission(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "You do not have permission to access this resource",
+				Error:   "forbidden",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// BecknSignatureAuth is a middleware that verifies the Ed25519 signature in the Authorization
+// header of Beckn network requests against the subscriber key registry.
+// The verified subscriber ID is stored under "subscriber_id" and the signature expiry under
+// "signature_expires".
+func BecknSignatureAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, beckn.NewNack(beckn.ErrCodeInvalidRequest, "failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if digest := c.GetHeader("Digest"); digest != "" && digest != beckn.Digest(body) {
+			c.Header("WWW-Authenticate", beckn.Challenge())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, beckn.NewNack(beckn.ErrCodeInvalidSignature, "digest does not match the request body"))
+			return
+		}
+
+		sig, err := beckn.VerifySignature(c.GetHeader("Authorization"), body, beckn.Registry, time.Now())
+		if err != nil {
+			c.Header("WWW-Authenticate", beckn.Challenge())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, beckn.NewNack(beckn.ErrCodeInvalidSignature, err.Error()))
+			return
+		}
+
+		c.Set("subscriber_id", sig.SubscriberID)
+		c.Set("signature_expires", time.Unix(sig.Expires, 0))
 		c.Next()
 	}
 }
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		role       interface{}
+		permission models.Permission
+		want       int
+	}{
+		{models.RoleApplicant, models.PermissionApplicationOwn, http.StatusOK},
+		{models.RoleApplicant, models.PermissionApplicationReview, http.StatusForbidden},
+		{models.RoleApplicant, models.PermissionSchemeManage, http.StatusForbidden},
+		{models.RoleReviewer, models.PermissionApplicationReview, http.StatusOK},
+		{models.RoleReviewer, models.PermissionApplicationOwn, http.StatusForbidden},
+		{models.RoleReviewer, models.PermissionUserManage, http.StatusForbidden},
+		{models.RoleAdmin, models.PermissionSchemeManage, http.StatusOK},
+		{models.RoleAdmin, models.PermissionUserManage, http.StatusOK},
+		{models.RoleAdmin, models.PermissionApplicationOwn, http.StatusForbidden},
+		{models.Role("superuser"), models.PermissionUserManage, http.StatusForbidden},
+		{"admin", models.PermissionUserManage, http.StatusUnauthorized}, // not a models.Role
+		{nil, models.PermissionApplicationOwn, http.StatusUnauthorized},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		r := gin.New()
+		r.GET("/", func(c *gin.Context) {
+			if tt.role != nil {
+				c.Set("role", tt.role)
+			}
+		}, RequirePermission(tt.permission), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != tt.want {
+			t.Errorf("role %v with %s: status %d, want %d", tt.role, tt.permission, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// Package migrations applies the numbered SQL migrations in sql/ and tracks
+// the applied versions in the schema_version table.
+//
+// Every migration is a pair of files named NNNN_name.up.sql and NNNN_name.down.sql.
+// Each file runs in its own transaction together with the schema_version update.
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// ErrSchemaOutdated is returned when the database is not at the latest migration version.
+var ErrSchemaOutdated = errors.New("database schema is out of date")
+
+// ErrUnknownVersion is returned when a target version does not exist.
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+var filePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// lockID is the advisory lock key held while migrations run, so concurrent runs serialize.
+const lockID = 7271017
+
+// Migration is a numbered schema change with its rollback.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes a migration and whether it is applied.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// schemaVersion is a row of the schema_version table.
+type schemaVersion struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaVersion) TableName() string { return "schema_version" }
+
+const createVersionTable = `CREATE TABLE IF NOT EXISTS "schema_version" (
+	"version" integer PRIMARY KEY,
+	"name" text NOT NULL,
+	"applied_at" timestamptz NOT NULL
+)`
+
+// All returns the embedded migrations ordered by version.
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := filePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := files.ReadFile("sql/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest returns the highest embedded migration version.
+func Latest() (int, error) {
+	migrations, err := All()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Current returns the highest applied migration version, or 0 for an empty database.
+func Current(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&schemaVersion{}) {
+		return 0, nil
+	}
+	var version *int
+	if err := db.Model(&schemaVersion{}).Select("MAX(version)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version == nil {
+		return 0, nil
+	}
+	return *version, nil
+}
+
+// Check verifies that the database is at the latest migration version.
+//
+// Returns:
+// - error: An error wrapping ErrSchemaOutdated if migrations are pending or the database
+// is newer than this binary, or a database error.
+func Check(db *gorm.DB) error {
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+	current, err := Current(db)
+	if err != nil {
+		return err
+	}
+	if current < latest {
+		return fmt.Errorf("%w: database is at version %d, latest is %d", ErrSchemaOutdated, current, latest)
+	}
+	if current > latest {
+		return fmt.Errorf("%w: database version %d is newer than this build (%d)", ErrSchemaOutdated, current, latest)
+	}
+	return nil
+}
+
+// StatusOf lists all known migrations with the time they were applied.
+func StatusOf(db *gorm.DB) ([]Status, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		status := Status{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations.
+func Up(db *gorm.DB) ([]Migration, error) {
+	latest, err := Latest()
+	if err != nil {
+		return nil, err
+	}
+	return To(db, latest)
+}
+
+// Down reverts the given number of most recently applied migrations.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	target := 0
+	remaining := steps
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; !ok {
+			continue
+		}
+		if remaining == 0 {
+			target = migrations[i].Version
+			break
+		}
+		remaining--
+	}
+	return To(db, target)
+}
+
+// To migrates the database up or down to the given version. Version 0 reverts everything.
+//
+// Parameters:
+// - db (*gorm.DB): The database connection.
+// - version (int): The target version.
+//
+// Returns:
+// - []Migration: The migrations that were applied or reverted, in execution order.
+// - error: ErrUnknownVersion for a version that does not exist, or the error of the failed migration.
+func To(db *gorm.DB, version int) ([]Migration, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && !hasVersion(migrations, version) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	if err := db.Exec(createVersionTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			break
+		}
+		ran, err := apply(db, m, true)
+		if err != nil {
+			return done, err
+		}
+		if ran {
+			done = append(done, m)
+		}
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= version {
+			break
+		}
+		ran, err := apply(db, m, false)
+		if err != nil {
+			return done, err
+		}
+		if ran {
+			done = append(done, m)
+		}
+	}
+	return done, nil
+}
+
+// apply runs one direction of a migration unless the database is already in that state.
+// It reports whether the migration ran.
+func apply(db *gorm.DB, m Migration, up bool) (bool, error) {
+	ran := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&schemaVersion{}).Where("version = ?", m.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if (count > 0) == up {
+			return nil
+		}
+
+		if up {
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaVersion{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+		if err := tx.Exec(m.Down).Error; err != nil {
+			return err
+		}
+		ran = true
+		return tx.Delete(&schemaVersion{}, m.Version).Error
+	})
+	if err != nil {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		return false, fmt.Errorf("migration %d_%s %s failed: %w", m.Version, m.Name, direction, err)
+	}
+	return ran, nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int]schemaVersion, error) {
+	applied := make(map[int]schemaVersion)
+	if !db.Migrator().HasTable(&schemaVersion{}) {
+		return applied, nil
+	}
+	var rows []schemaVersion
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema versions: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func hasVersion(migrations []Migration, version int) bool {
+	for _, m := range migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
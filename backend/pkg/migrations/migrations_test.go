@@ -0,0 +1,193 @@
+package migrations_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/migrations"
+)
+
+func TestAll(t *testing.T) {
+	all, err := migrations.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range all {
+		if m.Version != i+1 {
+			t.Errorf("migration %d_%s, want version %d", m.Version, m.Name, i+1)
+		}
+	}
+	latest, err := migrations.Latest()
+	if err != nil || latest != len(all) {
+		t.Errorf("Latest() = %d, %v, want %d", latest, err, len(all))
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	conn := dbtest.Open(t)
+	latest, err := migrations.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrations.Check(conn); !errors.Is(err, migrations.ErrSchemaOutdated) {
+		t.Errorf("Check() on an empty database = %v, want ErrSchemaOutdated", err)
+	}
+	done, err := migrations.Up(conn)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(done) != latest {
+		t.Errorf("Up() applied %d migrations, want %d", len(done), latest)
+	}
+	if err := migrations.Check(conn); err != nil {
+		t.Errorf("Check() after Up() = %v", err)
+	}
+	if done, err := migrations.Up(conn); err != nil || len(done) != 0 {
+		t.Errorf("second Up() = %d migrations, %v, want none", len(done), err)
+	}
+
+	if _, err := migrations.Down(conn, 1); err != nil {
+		t.Fatalf("Down(1) error = %v", err)
+	}
+	if current, err := migrations.Current(conn); err != nil || current != latest-1 {
+		t.Errorf("Current() after Down(1) = %d, %v, want %d", current, err, latest-1)
+	}
+
+	if _, err := migrations.To(conn, 0); err != nil {
+		t.Fatalf("To(0) error = %v", err)
+	}
+	if current, err := migrations.Current(conn); err != nil || current != 0 {
+		t.Errorf("Current() after To(0) = %d, %v, want 0", current, err)
+	}
+	if conn.Migrator().HasTable("users") {
+		t.Error("users table still exists after reverting every migration")
+	}
+
+	if _, err := migrations.To(conn, latest+1); !errors.Is(err, migrations.ErrUnknownVersion) {
+		t.Errorf("To(%d) error = %v, want ErrUnknownVersion", latest+1, err)
+	}
+	if _, err := migrations.Up(conn); err != nil {
+		t.Fatalf("Up() after reverting error = %v", err)
+	}
+}
+
+// legacySchema is the schema AutoMigrate created before versioned migrations, for the tables
+// whose columns changed since.
+const legacySchema = `
+CREATE TABLE "users" (
+    "id" bigserial PRIMARY KEY,
+    "username" text NOT NULL CONSTRAINT "uni_users_username" UNIQUE,
+    "password" text NOT NULL
+);
+CREATE TABLE "student_profiles" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "full_name" text NOT NULL,
+    "date_of_birth" timestamptz,
+    "gender" varchar(10),
+    "phone_number" varchar(15),
+    "qualification" varchar(50),
+    "email" varchar(100),
+    "aadhaar_number" varchar(12),
+    "nationality" text,
+    "category" varchar(20),
+    "income" decimal,
+    "is_international" boolean,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE TABLE "upload_documents" (
+    "id" bigserial PRIMARY KEY,
+    "student_id" bigint NOT NULL REFERENCES "student_profiles"("id"),
+    "name" text,
+    "url" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE TABLE "eligibilities" (
+    "id" bigserial PRIMARY KEY,
+    "gender" varchar(10),
+    "age_min" bigint,
+    "age_max" bigint,
+    "income_limit" decimal,
+    "academic_qualification" varchar(20),
+    "category" varchar(20),
+    "created_at" timestamptz,
+    "updated_at" timestamptz
+);
+CREATE TABLE "schemes" (
+    "id" bigserial PRIMARY KEY,
+    "name" text NOT NULL,
+    "description" text,
+    "eligibility_id" bigint REFERENCES "eligibilities"("id"),
+    "amount" decimal,
+    "application_link" text,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "status" varchar(20) DEFAULT 'upcoming',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz
+);
+CREATE TABLE "applications" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL REFERENCES "users"("id"),
+    "scheme_id" bigint NOT NULL REFERENCES "schemes"("id"),
+    "student_profile_id" bigint NOT NULL REFERENCES "student_profiles"("id"),
+    "is_draft" boolean DEFAULT true,
+    "verified" boolean DEFAULT false,
+    "submitted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "status" varchar(20)
+);
+INSERT INTO "users" ("username", "password") VALUES ('legacy', 'hash');
+INSERT INTO "student_profiles" ("user_id", "full_name") VALUES (1, 'Legacy Applicant');
+INSERT INTO "upload_documents" ("student_id", "name", "url") VALUES (1, 'aadhar_card', 'https://example.com/a.pdf');
+`
+
+func TestUpAdoptsAutoMigrateSchema(t *testing.T) {
+	conn := dbtest.Open(t)
+	if err := conn.Exec(legacySchema).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrations.Up(conn); err != nil {
+		t.Fatalf("Up() on a legacy schema error = %v", err)
+	}
+	if err := migrations.Check(conn); err != nil {
+		t.Errorf("Check() after Up() = %v", err)
+	}
+
+	var role string
+	if err := conn.Raw(`SELECT "role" FROM "users" WHERE "username" = 'legacy'`).Scan(&role).Error; err != nil || role != "applicant" {
+		t.Errorf("legacy user role = %q, %v, want applicant", role, err)
+	}
+	var status string
+	if err := conn.Raw(`SELECT "verification_status" FROM "upload_documents"`).Scan(&status).Error; err != nil || status != "pending" {
+		t.Errorf("legacy document verification_status = %q, %v, want pending", status, err)
+	}
+	for table, columns := range map[string][]string{
+		"users":            {"email", "is_active", "deactivated_at"},
+		"upload_documents": {"storage_key", "checksum", "verified_by_id"},
+		"applications":     {"status_reason", "assigned_reviewer_id", "assigned_at"},
+		"student_profiles": {"is_primary"},
+	} {
+		for _, column := range columns {
+			if !conn.Migrator().HasColumn(table, column) {
+				t.Errorf("%s.%s missing after Up()", table, column)
+			}
+		}
+	}
+	for table, index := range map[string]string{
+		"users":            "idx_users_email",
+		"upload_documents": "idx_upload_documents_storage_key",
+		"applications":     "idx_applications_assigned_reviewer_id",
+	} {
+		if !conn.Migrator().HasIndex(table, index) {
+			t.Errorf("index %s missing after Up()", index)
+		}
+	}
+}
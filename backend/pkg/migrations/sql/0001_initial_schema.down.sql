@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "beckn_orders";
+DROP TABLE IF EXISTS "refresh_tokens";
+DROP TABLE IF EXISTS "password_reset_tokens";
+DROP TABLE IF EXISTS "application_status_histories";
+DROP TABLE IF EXISTS "applications";
+DROP TABLE IF EXISTS "upload_documents";
+DROP TABLE IF EXISTS "student_academic_qualifications";
+DROP TABLE IF EXISTS "addresses";
+DROP TABLE IF EXISTS "student_profiles";
+DROP TABLE IF EXISTS "eligibility_document_maps";
+DROP TABLE IF EXISTS "schemes";
+DROP TABLE IF EXISTS "eligibilities";
+DROP TABLE IF EXISTS "documents_requireds";
+DROP TABLE IF EXISTS "users";
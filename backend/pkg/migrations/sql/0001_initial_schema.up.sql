@@ -0,0 +1,234 @@
+-- Baseline schema. Tables are created only if missing so databases that were
+-- previously set up by AutoMigrate can adopt versioned migrations; the columns
+-- those tables lack are added at the end.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "username" text NOT NULL,
+    "email" varchar(100),
+    "password" text NOT NULL,
+    "role" varchar(20) NOT NULL DEFAULT 'applicant',
+    "is_active" boolean NOT NULL DEFAULT true,
+    "deactivated_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username")
+);
+
+CREATE TABLE IF NOT EXISTS "documents_requireds" (
+    "id" bigserial,
+    "name" text,
+    "description" text,
+    "type" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_documents_requireds_name" ON "documents_requireds" ("name");
+
+CREATE TABLE IF NOT EXISTS "eligibilities" (
+    "id" bigserial,
+    "gender" varchar(10),
+    "age_min" bigint,
+    "age_max" bigint,
+    "income_limit" decimal,
+    "academic_qualification" varchar(20),
+    "category" varchar(20),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "schemes" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "description" text,
+    "eligibility_id" bigint,
+    "amount" decimal,
+    "application_link" text,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "status" varchar(20) DEFAULT 'upcoming',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_schemes_eligibility" FOREIGN KEY ("eligibility_id") REFERENCES "eligibilities"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_schemes_deleted_at" ON "schemes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "eligibility_document_maps" (
+    "id" bigserial,
+    "eligibility_id" bigint,
+    "document_id" bigint,
+    "is_mandatory" boolean,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_eligibilities_document_mappings" FOREIGN KEY ("eligibility_id") REFERENCES "eligibilities"("id"),
+    CONSTRAINT "fk_documents_requireds_eligibility_documents" FOREIGN KEY ("document_id") REFERENCES "documents_requireds"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "student_profiles" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "full_name" text NOT NULL,
+    "date_of_birth" timestamptz,
+    "gender" varchar(10),
+    "phone_number" varchar(15),
+    "qualification" varchar(50),
+    "email" varchar(100),
+    "aadhaar_number" varchar(12),
+    "nationality" text,
+    "category" varchar(20),
+    "income" decimal,
+    "is_international" boolean,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "addresses" (
+    "id" bigserial,
+    "student_id" bigint NOT NULL,
+    "type" varchar(20),
+    "street" text,
+    "city" text,
+    "state" text,
+    "pincode" varchar(10),
+    "country" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_student_profiles_addresses" FOREIGN KEY ("student_id") REFERENCES "student_profiles"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "student_academic_qualifications" (
+    "id" bigserial,
+    "student_id" bigint NOT NULL,
+    "degree" text,
+    "university" text,
+    "year_of_passing" bigint,
+    "grade" text,
+    "course" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_student_profiles_education_history" FOREIGN KEY ("student_id") REFERENCES "student_profiles"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "upload_documents" (
+    "id" bigserial,
+    "student_id" bigint NOT NULL,
+    "name" text,
+    "url" text,
+    "storage_key" text,
+    "content_type" varchar(100),
+    "size" bigint,
+    "checksum" varchar(64),
+    "verification_status" varchar(20) NOT NULL DEFAULT 'pending',
+    "rejection_reason" text,
+    "verified_by_id" bigint,
+    "verified_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_student_profiles_documents" FOREIGN KEY ("student_id") REFERENCES "student_profiles"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "applications" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "scheme_id" bigint NOT NULL,
+    "student_profile_id" bigint NOT NULL,
+    "is_draft" boolean DEFAULT true,
+    "verified" boolean DEFAULT false,
+    "submitted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "status" varchar(20),
+    "status_reason" text,
+    "assigned_reviewer_id" bigint,
+    "assigned_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_applications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_applications_scheme" FOREIGN KEY ("scheme_id") REFERENCES "schemes"("id"),
+    CONSTRAINT "fk_applications_student_profile" FOREIGN KEY ("student_profile_id") REFERENCES "student_profiles"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "application_status_histories" (
+    "id" bigserial,
+    "application_id" bigint NOT NULL,
+    "from_status" varchar(20),
+    "to_status" varchar(20) NOT NULL,
+    "actor_id" bigint NOT NULL,
+    "reason" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_application_status_histories_application_id" ON "application_status_histories" ("application_id");
+CREATE INDEX IF NOT EXISTS "idx_application_status_histories_created_at" ON "application_status_histories" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "password_reset_tokens" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_password_reset_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_password_reset_tokens_token_hash" ON "password_reset_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_password_reset_tokens_user_id" ON "password_reset_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "refresh_tokens" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "replaced_by_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_refresh_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "beckn_orders" (
+    "id" bigserial,
+    "application_id" bigint NOT NULL,
+    "bap_id" text NOT NULL,
+    "transaction_id" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_beckn_orders_application" FOREIGN KEY ("application_id") REFERENCES "applications"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_beckn_orders_application_id" ON "beckn_orders" ("application_id");
+CREATE INDEX IF NOT EXISTS "idx_beckn_orders_bap_id" ON "beckn_orders" ("bap_id");
+
+-- Adopt databases created by AutoMigrate before these columns existed.
+ALTER TABLE "users"
+    ADD COLUMN IF NOT EXISTS "email" varchar(100),
+    ADD COLUMN IF NOT EXISTS "role" varchar(20) NOT NULL DEFAULT 'applicant',
+    ADD COLUMN IF NOT EXISTS "is_active" boolean NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS "deactivated_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "created_at" timestamptz,
+    ADD COLUMN IF NOT EXISTS "updated_at" timestamptz;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+ALTER TABLE "upload_documents"
+    ADD COLUMN IF NOT EXISTS "storage_key" text,
+    ADD COLUMN IF NOT EXISTS "content_type" varchar(100),
+    ADD COLUMN IF NOT EXISTS "size" bigint,
+    ADD COLUMN IF NOT EXISTS "checksum" varchar(64),
+    ADD COLUMN IF NOT EXISTS "verification_status" varchar(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS "rejection_reason" text,
+    ADD COLUMN IF NOT EXISTS "verified_by_id" bigint,
+    ADD COLUMN IF NOT EXISTS "verified_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_upload_documents_storage_key" ON "upload_documents" ("storage_key");
+
+ALTER TABLE "applications"
+    ADD COLUMN IF NOT EXISTS "status" varchar(20),
+    ADD COLUMN IF NOT EXISTS "status_reason" text,
+    ADD COLUMN IF NOT EXISTS "assigned_reviewer_id" bigint,
+    ADD COLUMN IF NOT EXISTS "assigned_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_applications_assigned_reviewer_id" ON "applications" ("assigned_reviewer_id");
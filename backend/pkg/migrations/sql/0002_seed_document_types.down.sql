@@ -0,0 +1,7 @@
+-- Document types still referenced by a scheme are kept.
+DELETE FROM "documents_requireds"
+WHERE "name" IN (
+    'aadhar_card', 'pan_card', 'driving_license', 'class_x_certificate', 'class_xii_certificate',
+    'diploma_certificate', 'graduation_certificate', 'post_grad_certificate', 'passport', 'other'
+)
+AND "id" NOT IN (SELECT "document_id" FROM "eligibility_document_maps" WHERE "document_id" IS NOT NULL);
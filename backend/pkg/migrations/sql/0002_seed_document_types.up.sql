@@ -0,0 +1,12 @@
+INSERT INTO "documents_requireds" ("name", "description", "type") VALUES
+    ('aadhar_card', 'Aadhar Card', 'identity'),
+    ('pan_card', 'PAN Card', 'identity'),
+    ('driving_license', 'Driving License', 'identity'),
+    ('class_x_certificate', 'Class X Certificate', 'education'),
+    ('class_xii_certificate', 'Class XII Certificate', 'education'),
+    ('diploma_certificate', 'Diploma Certificate', 'education'),
+    ('graduation_certificate', 'Graduation Certificate', 'education'),
+    ('post_grad_certificate', 'Post Graduation Certificate', 'education'),
+    ('passport', 'Passport', 'identity'),
+    ('other', 'Other Document', 'other')
+ON CONFLICT ("name") DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_student_profiles_primary";
+ALTER TABLE "student_profiles" DROP COLUMN IF EXISTS "is_primary";
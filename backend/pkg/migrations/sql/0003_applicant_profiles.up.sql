@@ -0,0 +1,4 @@
+-- The saved profile of an applicant is the student profile marked primary; drafts edit it
+-- and every submission copies it into a profile of its own.
+ALTER TABLE "student_profiles" ADD COLUMN IF NOT EXISTS "is_primary" boolean NOT NULL DEFAULT false;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_student_profiles_primary" ON "student_profiles" ("user_id") WHERE "is_primary";
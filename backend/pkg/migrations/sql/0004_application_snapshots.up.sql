@@ -0,0 +1,12 @@
+-- Every submission freezes the application data as a new snapshot version; rows are never changed.
+CREATE TABLE IF NOT EXISTS "application_snapshots" (
+    "id" bigserial,
+    "application_id" bigint NOT NULL,
+    "version" bigint NOT NULL,
+    "submitted_by" bigint NOT NULL,
+    "data" jsonb NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_application_snapshots_application" FOREIGN KEY ("application_id") REFERENCES "applications"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_application_snapshots_version" ON "application_snapshots" ("application_id","version");
@@ -0,0 +1,2 @@
+-- The placeholder dates carried no information, so there is nothing to restore.
+SELECT 1;
@@ -0,0 +1,3 @@
+-- Profiles saved before birth dates became optional stored a missing date as 0001-01-01;
+-- those are unknown dates and are cleared so they read back as null.
+UPDATE "student_profiles" SET "date_of_birth" = NULL WHERE "date_of_birth" < '1900-01-01';
@@ -1,34 +1,71 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID       uint   `gorm:"primaryKey" json:"id"`
-	Username string `gorm:"unique;not null" json:"username"`
-	Password string `gorm:"not null" json:"-"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Username      string     `gorm:"unique;not null" json:"username"`
+	Email         *string    `gorm:"type:varchar(100);uniqueIndex" json:"email,omitempty"`
+	Password      string     `gorm:"not null" json:"-"`
+	Role          Role       `gorm:"type:varchar(20);not null;default:'applicant'" json:"role"`
+	IsActive      bool       `gorm:"not null;default:true" json:"is_active"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 type UploadDocument struct {
-	ID             uint           `gorm:"primaryKey" json:"-"`
-	StudentID      uint           `gorm:"not null" json:"-"` // Foreign key to StudentProfile
-	Name           string         `json:"name"`
-	URL            string         `json:"url"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	StudentProfile StudentProfile `gorm:"foreignKey:StudentID" json:"-"`
+	ID                 uint                       `gorm:"primaryKey" json:"id"`
+	StudentID          uint                       `gorm:"not null" json:"-"` // Foreign key to StudentProfile
+	Name               string                     `json:"name"`
+	URL                string                     `json:"url"`            // for stored files the unsigned storage.FilePath
+	StorageKey         string                     `gorm:"index" json:"-"` // set when the file is stored by this service
+	ContentType        string                     `gorm:"type:varchar(100)" json:"content_type,omitempty"`
+	Size               int64                      `json:"size,omitempty"`
+	Checksum           string                     `gorm:"type:varchar(64)" json:"checksum,omitempty"` // hex SHA-256 of the file
+	VerificationStatus DocumentVerificationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"verification_status"`
+	RejectionReason    string                     `json:"rejection_reason,omitempty"`
+	VerifiedByID       *uint                      `json:"verified_by_id,omitempty"`
+	VerifiedAt         *time.Time                 `json:"verified_at,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
+	UpdatedAt          time.Time                  `json:"updated_at"`
+	StudentProfile     StudentProfile             `gorm:"foreignKey:StudentID" json:"-"`
 }
 
+// MarshalJSON returns a stored file with a freshly signed download link as its URL, so links
+// are never persisted and expire shortly after they are handed out.
+func (d UploadDocument) MarshalJSON() ([]byte, error) {
+	type document UploadDocument
+	if d.StorageKey != "" {
+		if link, err := storage.SignURL(d.StorageKey); err == nil {
+			d.URL = link
+		}
+	}
+	return json.Marshal(document(d))
+}
+
+// DocumentVerificationStatus is the review state of a single uploaded document.
+type DocumentVerificationStatus string
+
+const (
+	DocumentPending  DocumentVerificationStatus = "pending"
+	DocumentVerified DocumentVerificationStatus = "verified"
+	DocumentRejected DocumentVerificationStatus = "rejected"
+)
+
 const (
 	AddressTypePermanent = "permanent"
 	AddressTypeCurrent   = "current"
 )
 
 type Address struct {
-	ID        uint      `gorm:"primaryKey" json:"-"`
+	ID        uint      `gorm:"primaryKey" json:"id"`
 	StudentID uint      `gorm:"not null" json:"-"`
 	Type      string    `gorm:"type:varchar(20)" json:"type"` // permanent, current
 	Street    string    `json:"street"`
@@ -41,7 +78,7 @@ type Address struct {
 }
 
 type StudentAcademicQualification struct {
-	ID            uint      `gorm:"primaryKey" json:"-"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
 	StudentID     uint      `gorm:"not null" json:"-"`
 	Degree        string    `json:"degree"`
 	University    string    `json:"university"`
@@ -56,7 +93,7 @@ type StudentProfile struct {
 	ID               uint                           `gorm:"primaryKey" json:"-"`
 	UserID           uint                           `gorm:"not null;" json:"-"`
 	FullName         string                         `gorm:"not null" json:"full_name"`
-	DateOfBirth      time.Time                      `json:"date_of_birth"`
+	DateOfBirth      *time.Time                     `json:"date_of_birth"`
 	Gender           string                         `gorm:"type:varchar(10)" json:"gender"`
 	PhoneNumber      string                         `gorm:"type:varchar(15)" json:"phone_number"`
 	Qualification    string                         `gorm:"type:varchar(50)" json:"qualification"`
@@ -65,7 +102,8 @@ type StudentProfile struct {
 	Nationality      string                         `json:"nationality"` // Added Nationality
 	Category         string                         `gorm:"type:varchar(20)" json:"category"`
 	Income           float64                        `json:"income"`
-	IsInternational  bool                           `json:"is_international"` // Flag to mark international students
+	IsInternational  bool                           `json:"is_international"`                // Flag to mark international students
+	IsPrimary        bool                           `gorm:"not null;default:false" json:"-"` // the applicant's saved profile, shared by their drafts
 	CreatedAt        time.Time                      `json:"created_at"`
 	UpdatedAt        time.Time                      `json:"updated_at"`
 	Documents        []UploadDocument               `gorm:"foreignKey:StudentID" json:"documents"`
@@ -75,59 +113,68 @@ type StudentProfile struct {
 
 // Application represents a scholarship application
 type Application struct {
-	ID               uint           `gorm:"primaryKey" json:"id"`
-	UserID           uint           `gorm:"not null" json:"user_id"`
-	SchemeID         uint           `gorm:"not null" json:"scheme_id"`
-	StudentProfileID uint           `gorm:"not null" json:"student_profile_id"`
-	IsDraft          bool           `gorm:"default:true" json:"is_draft"`
-	Verified         bool           `gorm:"default:false" json:"verified"`
-	SubmittedAt      *time.Time     `json:"submitted_at,omitempty"`
-	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	User             User           `gorm:"foreignKey:UserID" json:"user"`
-	Scheme           Scheme         `gorm:"foreignKey:SchemeID" json:"-"`
-	StudentProfile   StudentProfile `gorm:"foreignKey:StudentProfileID" json:"student_profile"`
-	Status           string         `gorm:"type:varchar(20)" json:"status"` // status of the application (submitted, under review, approved, rejected)
+	ID                 uint              `gorm:"primaryKey" json:"id"`
+	UserID             uint              `gorm:"not null" json:"user_id"`
+	SchemeID           uint              `gorm:"not null" json:"scheme_id"`
+	StudentProfileID   uint              `gorm:"not null" json:"student_profile_id"`
+	IsDraft            bool              `gorm:"default:true" json:"is_draft"`
+	Verified           bool              `gorm:"default:false" json:"verified"`
+	SubmittedAt        *time.Time        `json:"submitted_at,omitempty"`
+	CreatedAt          time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	User               User              `gorm:"foreignKey:UserID" json:"user"`
+	Scheme             Scheme            `gorm:"foreignKey:SchemeID" json:"-"`
+	StudentProfile     StudentProfile    `gorm:"foreignKey:StudentProfileID" json:"student_profile"`
+	Status             ApplicationStatus `gorm:"type:varchar(20)" json:"status"` // see ApplicationStatus for the state machine
+	StatusReason       string            `json:"status_reason,omitempty"`        // reason given for the latest reviewer decision
+	AssignedReviewerID *uint             `gorm:"index" json:"assigned_reviewer_id,omitempty"`
+	AssignedAt         *time.Time        `json:"assigned_at,omitempty"`
 }
 
+// The validate tags of the input types below are checked by utils.ValidateStudentProfileInput.
+// Empty values are accepted everywhere because drafts are filled in step by step.
+
 type DocumentInput struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	ID   uint   `json:"id,omitempty"` // set to change an existing document; documents without an ID are matched by name
+	Name string `json:"name" validate:"max=100"`
+	URL  string `json:"url" validate:"max=2048"`
 }
 
 type AddressInput struct {
-	Type    string `json:"type"` // e.g., permanent, current
-	Street  string `json:"street"`
-	City    string `json:"city"`
-	State   string `json:"state"`
-	Pincode string `json:"pincode"`
-	Country string `json:"country"`
+	ID      uint   `json:"id,omitempty"`                                      // set to change an existing address; addresses without an ID are matched by type
+	Type    string `json:"type" validate:"omitempty,oneof=permanent current"` // e.g., permanent, current
+	Street  string `json:"street" validate:"max=255"`
+	City    string `json:"city" validate:"max=100"`
+	State   string `json:"state" validate:"max=100"`
+	Pincode string `json:"pincode" validate:"omitempty,pincode"`
+	Country string `json:"country" validate:"max=100"`
 }
 
 type EducationHistoryInput struct {
-	Degree        string `json:"degree"`
-	University    string `json:"university"`
-	YearOfPassing int    `json:"year_of_passing"`
-	Grade         string `json:"grade"`
-	Course        string `json:"course"`
+	ID            uint   `json:"id,omitempty"` // set to change an existing entry; entries without an ID are added
+	Degree        string `json:"degree" validate:"max=100"`
+	University    string `json:"university" validate:"max=255"`
+	YearOfPassing int    `json:"year_of_passing" validate:"omitempty,year"`
+	Grade         string `json:"grade" validate:"max=20"`
+	Course        string `json:"course" validate:"max=100"`
 }
 
 type StudentProfileInput struct {
-	FullName         string                  `json:"full_name"`
-	DateOfBirth      *time.Time              `json:"date_of_birth"`
-	Gender           string                  `json:"gender"`
-	PhoneNumber      string                  `json:"phone_number"`
-	Qualification    string                  `json:"qualification"`
-	Email            string                  `json:"email"`
-	AadhaarNumber    string                  `json:"aadhaar_number"`
-	Nationality      string                  `json:"nationality"`
-	Category         string                  `json:"category"`
-	Income           *float64                `json:"income"`
-	PassportNumber   string                  `json:"passport_number"`
+	FullName         string                  `json:"full_name" validate:"max=255"`
+	DateOfBirth      *time.Time              `json:"date_of_birth" validate:"omitempty,dob"`
+	Gender           string                  `json:"gender" validate:"omitempty,gender" example:"Female"`
+	PhoneNumber      string                  `json:"phone_number" validate:"omitempty,phone" example:"+919876543210"`
+	Qualification    string                  `json:"qualification" validate:"omitempty,qualification" example:"Class-XII"`
+	Email            string                  `json:"email" validate:"omitempty,email,max=255"`
+	AadhaarNumber    string                  `json:"aadhaar_number" validate:"omitempty,aadhaar" example:"234123412346"`
+	Nationality      string                  `json:"nationality" validate:"max=100"`
+	Category         string                  `json:"category" validate:"omitempty,category" example:"OBC"`
+	Income           *float64                `json:"income" validate:"omitempty,gte=0"`
+	PassportNumber   string                  `json:"passport_number" validate:"omitempty,alphanum,max=20"`
 	IsInternational  bool                    `json:"is_international"`
-	Documents        []DocumentInput         `json:"documents"`
-	Addresses        []AddressInput          `json:"addresses"`
-	EducationHistory []EducationHistoryInput `json:"education_history"`
+	Documents        []DocumentInput         `json:"documents" validate:"dive"`
+	Addresses        []AddressInput          `json:"addresses" validate:"dive"`
+	EducationHistory []EducationHistoryInput `json:"education_history" validate:"dive"`
 }
 
 func (a *Address) BeforeCreate(tx *gorm.DB) error {
@@ -170,3 +217,29 @@ type InitApplicationRequest struct {
 type SubmitExistingApplicationRequest struct {
 	ApplicationID uint `json:"application_id" binding:"required"`
 }
+
+// VerifyDocumentRequest is the payload used by reviewers to verify or reject an uploaded document
+type VerifyDocumentRequest struct {
+	Status DocumentVerificationStatus `json:"status" binding:"required" example:"rejected"`
+	Reason string                     `json:"reason" example:"Certificate scan is blurry"`
+}
+
+// ReuploadDocumentRequest is the payload used by applicants to replace an uploaded document
+type ReuploadDocumentRequest struct {
+	URL string `json:"url" binding:"required" example:"https://files.example.com/class_xii.pdf"`
+}
+
+// AssignReviewerRequest is the payload used to assign an application to a reviewer
+type AssignReviewerRequest struct {
+	ReviewerID uint `json:"reviewer_id" binding:"required" example:"4"`
+}
+
+// ApplicationFilter represents the filter criteria of the reviewer queue
+type ApplicationFilter struct {
+	SchemeID        *uint      `form:"scheme_id" example:"1"`
+	Status          *string    `form:"status" example:"submitted"`
+	SubmittedAfter  *time.Time `form:"submitted_after" example:"2023-01-01T00:00:00Z"`
+	SubmittedBefore *time.Time `form:"submitted_before" example:"2023-12-31T23:59:59Z"`
+	Category        *string    `form:"category" example:"SC"`
+	AssignedTo      *string    `form:"assigned_to" example:"me"` // reviewer ID, "me" or "unassigned"
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+)
+
+func TestUploadDocumentMarshalSignsStoredFiles(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("PUBLIC_BASE_URL", "")
+
+	stored := UploadDocument{Name: "aadhar_card", URL: storage.FilePath("a/b.pdf"), StorageKey: "a/b.pdf"}
+	external := UploadDocument{Name: "pan_card", URL: "https://files.example.org/pan.pdf"}
+
+	var got struct {
+		URL        string `json:"url"`
+		StorageKey string `json:"storage_key"`
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(raw, &got)
+	if !strings.HasPrefix(got.URL, "/api/v1/files/a/b.pdf?") || !strings.Contains(got.URL, "signature=") {
+		t.Errorf("stored file URL = %q, want a signed link", got.URL)
+	}
+	if strings.Contains(string(raw), "a/b.pdf\"") {
+		t.Errorf("the storage key is exposed: %s", raw)
+	}
+	if stored.URL != storage.FilePath("a/b.pdf") {
+		t.Error("marshalling changed the document")
+	}
+
+	raw, err = json.Marshal(external)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(raw, &got)
+	if got.URL != external.URL {
+		t.Errorf("external URL = %q, want it unchanged", got.URL)
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// PasswordResetToken is a single-use token that allows a user to set a new password.
+// Only the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primaryKey" json:"-"`
+	UserID    uint       `gorm:"not null;index" json:"-"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	User      User       `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// ChangePasswordRequest is the payload used by a user to change their own password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ResetPasswordRequest is the payload used to set a new password with a reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// PasswordResetResponse carries a freshly issued reset token to the administrator
+type PasswordResetResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RegisterRequest is the payload used for self-service signup
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required" example:"asha_k"`
+	Email    string `json:"email" binding:"required,email" example:"asha@example.com"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AvailabilityQuery holds the username and email to check for availability
+type AvailabilityQuery struct {
+	Username string `form:"username"`
+	Email    string `form:"email"`
+}
+
+// AvailabilityResponse reports whether a username and email can still be registered
+type AvailabilityResponse struct {
+	UsernameAvailable *bool `json:"username_available,omitempty"`
+	EmailAvailable    *bool `json:"email_available,omitempty"`
+}
+
+// DeactivateAccountRequest is the payload used by a user to deactivate their own account
+type DeactivateAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshToken is an opaque, single-use token that can be exchanged for a new access token.
+// Only the SHA-256 hash of the token is stored. Each use rotates it into ReplacedByID.
+type RefreshToken struct {
+	ID           uint       `gorm:"primaryKey" json:"-"`
+	UserID       uint       `gorm:"not null;index" json:"-"`
+	TokenHash    string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"-"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	User         User       `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// LoginRequest is the payload used to obtain an access and refresh token
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest is the payload used to rotate a refresh token or log out
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse carries a freshly issued token pair
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BecknOrder links an application to the Beckn network participant (BAP) it was created through.
+// Status and cancel requests are only honoured for the BAP that owns the order.
+type BecknOrder struct {
+	ID            uint        `gorm:"primaryKey" json:"-"`
+	ApplicationID uint        `gorm:"uniqueIndex;not null" json:"application_id"`
+	BapID         string      `gorm:"index;not null" json:"bap_id"`
+	TransactionID string      `json:"transaction_id"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	Application   Application `gorm:"foreignKey:ApplicationID" json:"-"`
+}
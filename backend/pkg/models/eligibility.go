@@ -0,0 +1,40 @@
+package models
+
+// CriterionResult is the outcome of checking a single eligibility criterion
+type CriterionResult struct {
+	Criterion string `json:"criterion" example:"age_max"`
+	Required  string `json:"required" example:"30"`
+	Actual    string `json:"actual" example:"32"`
+	Passed    bool   `json:"passed" example:"false"`
+	Reason    string `json:"reason,omitempty" example:"age 32 is above the maximum of 30"`
+}
+
+// EligibilityReport is the per-criterion outcome of checking a profile against a scheme
+type EligibilityReport struct {
+	SchemeID uint              `json:"scheme_id"`
+	Eligible bool              `json:"eligible"`
+	Criteria []CriterionResult `json:"criteria"`
+}
+
+// NearMissScheme is a scheme the profile missed by a small number of criteria
+type NearMissScheme struct {
+	Scheme  Scheme   `json:"scheme"`
+	Reasons []string `json:"reasons"`
+}
+
+// SchemeRecommendations lists the schemes a profile qualifies for and the ones it narrowly missed
+type SchemeRecommendations struct {
+	Eligible   []Scheme         `json:"eligible"`
+	NearMisses []NearMissScheme `json:"near_misses"`
+}
+
+// DocumentCheckReport lists the document problems that block a submission
+type DocumentCheckReport struct {
+	Missing []string `json:"missing" example:"class_xii_certificate"`
+	Unknown []string `json:"unknown" example:"marksheet"`
+}
+
+// OK reports whether no documents are missing or unknown.
+func (r DocumentCheckReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unknown) == 0
+}
@@ -0,0 +1,48 @@
+package models
+
+// Role identifies what a user is allowed to do in the system.
+type Role string
+
+const (
+	RoleApplicant Role = "applicant"
+	RoleReviewer  Role = "reviewer"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission names a single capability granted through a role.
+type Permission string
+
+const (
+	PermissionApplicationOwn    Permission = "application:own"
+	PermissionApplicationReview Permission = "application:review"
+	PermissionSchemeManage      Permission = "scheme:manage"
+	PermissionUserManage        Permission = "user:manage"
+)
+
+// RolePermissions maps every role to the permissions it grants
+var RolePermissions = map[Role][]Permission{
+	RoleApplicant: {PermissionApplicationOwn},
+	RoleReviewer:  {PermissionApplicationReview},
+	RoleAdmin:     {PermissionApplicationReview, PermissionSchemeManage, PermissionUserManage},
+}
+
+// IsValid reports whether the role is one of the known roles.
+func (r Role) IsValid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// HasPermission reports whether the role grants the given permission.
+func (r Role) HasPermission(p Permission) bool {
+	for _, granted := range RolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRoleRequest is the payload used by administrators to change a user's role
+type SetRoleRequest struct {
+	Role Role `json:"role" binding:"required" example:"reviewer"`
+}
@@ -17,6 +17,16 @@ const (
 	AcademicQualificationPostGraduate AcademicQualification = "Post-Graduate"
 )
 
+// AcademicQualifications lists the valid values of AcademicQualification, lowest first.
+var AcademicQualifications = []AcademicQualification{
+	AcademicQualificationNone,
+	AcademicQualificationClassX,
+	AcademicQualificationClassXII,
+	AcademicQualificationDiploma,
+	AcademicQualificationGraduate,
+	AcademicQualificationPostGraduate,
+}
+
 type Gender string
 
 const (
@@ -25,6 +35,9 @@ const (
 	GenderOther  Gender = "Other"
 )
 
+// Genders lists the valid values of Gender.
+var Genders = []Gender{GenderMale, GenderFemale, GenderOther}
+
 type Category string
 
 const (
@@ -35,6 +48,9 @@ const (
 	CategoryOther   Category = "Other"
 )
 
+// Categories lists the valid values of Category.
+var Categories = []Category{CategoryGeneral, CategorySC, CategoryST, CategoryOBC, CategoryOther}
+
 type Document string
 
 const (
@@ -64,6 +80,12 @@ var DefaultDocumentsRequired = []DocumentsRequired{
 	{Name: "other", Description: "Other Document", Type: "other"},
 }
 
+const (
+	SchemeStatusUpcoming = "upcoming"
+	SchemeStatusOpen     = "open"
+	SchemeStatusClosed   = "closed"
+)
+
 // ------------------ Core Models ------------------
 
 // Scheme represents a scholarship scheme
@@ -117,6 +139,88 @@ type Eligibility struct {
 	UpdatedAt             time.Time                `json:"updated_at"`
 }
 
+// ------------------ Admin Inputs ------------------
+
+// EligibilityDocumentInput references a required document by its DocumentsRequired name
+type EligibilityDocumentInput struct {
+	Name        string `json:"name" yaml:"name" binding:"required" example:"aadhar_card"`
+	IsMandatory bool   `json:"is_mandatory" yaml:"is_mandatory"`
+}
+
+// EligibilityInput is the payload used to create or replace eligibility criteria
+type EligibilityInput struct {
+	Gender                Gender                     `json:"gender" yaml:"gender,omitempty" example:"Female"`
+	AgeMin                int                        `json:"age_min" yaml:"age_min,omitempty" example:"18"`
+	AgeMax                int                        `json:"age_max" yaml:"age_max,omitempty" example:"30"`
+	IncomeLimit           float64                    `json:"income_limit" yaml:"income_limit,omitempty" example:"250000"`
+	AcademicQualification AcademicQualification      `json:"academic_qualification" yaml:"academic_qualification,omitempty" example:"Class-XII"`
+	Category              Category                   `json:"category" yaml:"category,omitempty" example:"General"`
+	Documents             []EligibilityDocumentInput `json:"documents_required" yaml:"documents_required,omitempty"`
+}
+
+// SchemeInput is the payload used to create or fully replace a scheme
+type SchemeInput struct {
+	Name            string           `json:"name" yaml:"name" binding:"required" example:"Scholar Scheme"`
+	Description     string           `json:"description" yaml:"description,omitempty"`
+	Amount          float64          `json:"amount" yaml:"amount" example:"5000"`
+	ApplicationLink string           `json:"application_link" yaml:"application_link,omitempty"`
+	StartDate       time.Time        `json:"start_date" yaml:"start_date" binding:"required" example:"2023-01-01T00:00:00Z"`
+	EndDate         time.Time        `json:"end_date" yaml:"end_date" binding:"required" example:"2023-12-31T23:59:59Z"`
+	Status          string           `json:"status" yaml:"status,omitempty" example:"upcoming"`
+	Eligibility     EligibilityInput `json:"eligibility" yaml:"eligibility"`
+}
+
+// SchemeImportRow is the outcome of importing one scheme definition.
+type SchemeImportRow struct {
+	Row      int    `json:"row"` // position in the file; the line number for CSV
+	Name     string `json:"name"`
+	Action   string `json:"action" example:"created"` // created, updated or failed
+	SchemeID uint   `json:"scheme_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SchemeImportReport summarizes a bulk scheme import.
+// Nothing is saved for a dry run or when any row failed.
+type SchemeImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Saved   bool              `json:"saved"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []SchemeImportRow `json:"rows"`
+}
+
+// Scheme import row actions.
+const (
+	SchemeImportCreated = "created"
+	SchemeImportUpdated = "updated"
+	SchemeImportFailed  = "failed"
+)
+
+// EligibilityPatchInput holds optional eligibility fields for partial updates.
+// A non-nil Documents slice replaces all document mappings.
+type EligibilityPatchInput struct {
+	Gender                *Gender                     `json:"gender"`
+	AgeMin                *int                        `json:"age_min"`
+	AgeMax                *int                        `json:"age_max"`
+	IncomeLimit           *float64                    `json:"income_limit"`
+	AcademicQualification *AcademicQualification      `json:"academic_qualification"`
+	Category              *Category                   `json:"category"`
+	Documents             *[]EligibilityDocumentInput `json:"documents_required"`
+}
+
+// SchemePatchInput holds optional scheme fields for partial updates
+type SchemePatchInput struct {
+	Name            *string                `json:"name"`
+	Description     *string                `json:"description"`
+	Amount          *float64               `json:"amount"`
+	ApplicationLink *string                `json:"application_link"`
+	StartDate       *time.Time             `json:"start_date"`
+	EndDate         *time.Time             `json:"end_date"`
+	Status          *string                `json:"status"`
+	Eligibility     *EligibilityPatchInput `json:"eligibility"`
+}
+
 // ------------------ Filtering ------------------
 
 // SchemeFilter represents filter criteria
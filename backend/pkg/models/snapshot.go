@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// SnapshotFormatVersion is the version of the ApplicationSnapshotData layout written by this build.
+// Version 1 kept documents by name with their URL; version 2 lists them by ID with their storage key.
+const SnapshotFormatVersion = 2
+
+// ApplicationSnapshot is the frozen content of an application at one submission.
+// Every submission of the same application gets the next version number.
+type ApplicationSnapshot struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	ApplicationID uint            `gorm:"not null;uniqueIndex:idx_application_snapshots_version" json:"application_id"`
+	Version       int             `gorm:"not null;uniqueIndex:idx_application_snapshots_version" json:"version"`
+	SubmittedBy   uint            `gorm:"not null" json:"submitted_by"`
+	Data          json.RawMessage `gorm:"type:jsonb;not null" json:"data" swaggertype:"object"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ErrSnapshotImmutable is returned when something tries to change or remove a submission snapshot.
+var ErrSnapshotImmutable = errors.New("application snapshots are immutable")
+
+func (s *ApplicationSnapshot) BeforeUpdate(tx *gorm.DB) error {
+	return ErrSnapshotImmutable
+}
+
+func (s *ApplicationSnapshot) BeforeDelete(tx *gorm.DB) error {
+	return ErrSnapshotImmutable
+}
+
+// MarshalJSON returns the snapshot with freshly signed download links for its stored documents.
+// Links are never written to the snapshot itself, so old submissions keep working links.
+func (s ApplicationSnapshot) MarshalJSON() ([]byte, error) {
+	type snapshot ApplicationSnapshot
+	var data ApplicationSnapshotData
+	if len(s.Data) > 0 && json.Unmarshal(s.Data, &data) == nil && data.FormatVersion >= 2 {
+		for i, doc := range data.Profile.Documents {
+			if doc.StorageKey == "" {
+				continue
+			}
+			if link, err := storage.SignURL(doc.StorageKey); err == nil {
+				data.Profile.Documents[i].URL = link
+			}
+		}
+		if raw, err := json.Marshal(data); err == nil {
+			s.Data = raw
+		}
+	}
+	return json.Marshal(snapshot(s))
+}
+
+// ApplicationSnapshotData is the JSON stored in ApplicationSnapshot.Data. Documents are ordered by
+// ID and addresses keyed by type so that diffs between submissions line up.
+type ApplicationSnapshotData struct {
+	FormatVersion int             `json:"format_version"`
+	ApplicationID uint            `json:"application_id"`
+	UserID        uint            `json:"user_id"`
+	SubmittedAt   *time.Time      `json:"submitted_at"`
+	Profile       ProfileSnapshot `json:"profile"`
+	Scheme        SchemeSnapshot  `json:"scheme"`
+}
+
+// ProfileSnapshot is the applicant data of a submission.
+type ProfileSnapshot struct {
+	FullName         string                  `json:"full_name"`
+	DateOfBirth      time.Time               `json:"date_of_birth"`
+	Gender           string                  `json:"gender"`
+	PhoneNumber      string                  `json:"phone_number"`
+	Qualification    string                  `json:"qualification"`
+	Email            string                  `json:"email"`
+	AadhaarNumber    string                  `json:"aadhaar_number"`
+	Nationality      string                  `json:"nationality"`
+	Category         string                  `json:"category"`
+	Income           float64                 `json:"income"`
+	IsInternational  bool                    `json:"is_international"`
+	Documents        []DocumentSnapshot      `json:"documents"` // ordered by document ID
+	Addresses        map[string]AddressInput `json:"addresses"` // by address type
+	EducationHistory []EducationHistoryInput `json:"education_history"`
+}
+
+// DocumentSnapshot is a document as it was attached to a submission. Stored files are kept by
+// storage key and only get a URL when the snapshot is returned; other documents keep their URL.
+type DocumentSnapshot struct {
+	ID                 uint                       `json:"id"`
+	Name               string                     `json:"name"`
+	StorageKey         string                     `json:"storage_key,omitempty"`
+	URL                string                     `json:"url,omitempty"`
+	ContentType        string                     `json:"content_type,omitempty"`
+	Size               int64                      `json:"size,omitempty"`
+	Checksum           string                     `json:"checksum,omitempty"`
+	VerificationStatus DocumentVerificationStatus `json:"verification_status"`
+	RejectionReason    string                     `json:"rejection_reason,omitempty"`
+}
+
+// SchemeSnapshot is the scheme definition, including eligibility and required documents,
+// that a submission was made against.
+type SchemeSnapshot struct {
+	ID uint `json:"id"`
+	SchemeInput
+}
+
+// ReviewApplication is an application as shown to reviewers, together with its latest submission.
+type ReviewApplication struct {
+	Application
+	LatestSnapshot *ApplicationSnapshot `json:"latest_snapshot"` // nil until the application is submitted
+}
+
+// SnapshotChange is one difference between two snapshots. Path is a JSON Pointer (RFC 6901)
+// into the snapshot data and Op is "add", "remove" or "replace" as in JSON Patch.
+type SnapshotChange struct {
+	Op       string      `json:"op" example:"replace"`
+	Path     string      `json:"path" example:"/profile/income"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// SnapshotDiff lists the changes between two submissions of an application.
+type SnapshotDiff struct {
+	ApplicationID uint             `json:"application_id"`
+	From          int              `json:"from"`
+	To            int              `json:"to"`
+	Changes       []SnapshotChange `json:"changes"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplicationSnapshotMarshalSignsDocuments(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", "0123456789abcdef0123456789abcdef")
+	t.Setenv("PUBLIC_BASE_URL", "")
+
+	data, err := json.Marshal(ApplicationSnapshotData{
+		FormatVersion: SnapshotFormatVersion,
+		Profile: ProfileSnapshot{Documents: []DocumentSnapshot{
+			{ID: 1, Name: "aadhar_card", StorageKey: "a/b.pdf"},
+			{ID: 2, Name: "pan_card", URL: "https://files.example.org/pan.pdf"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("/api/v1/files/")) {
+		t.Fatalf("stored snapshot data holds a link: %s", data)
+	}
+	snapshot := ApplicationSnapshot{ApplicationID: 1, Version: 1, Data: data}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Data ApplicationSnapshotData `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	docs := got.Data.Profile.Documents
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want 2", len(docs))
+	}
+	if !strings.HasPrefix(docs[0].URL, "/api/v1/files/a/b.pdf?") || !strings.Contains(docs[0].URL, "signature=") {
+		t.Errorf("stored file URL = %q, want a signed link", docs[0].URL)
+	}
+	if docs[1].URL != "https://files.example.org/pan.pdf" {
+		t.Errorf("external URL = %q, want it unchanged", docs[1].URL)
+	}
+	if !bytes.Equal(snapshot.Data, data) {
+		t.Error("marshalling changed the snapshot data")
+	}
+}
+
+func TestApplicationSnapshotMarshalKeepsVersion1(t *testing.T) {
+	data := json.RawMessage(`{"format_version":1,"profile":{"documents":{"aadhar_card":{"url":"/uploads/a.pdf"}}}}`)
+	raw, err := json.Marshal(ApplicationSnapshot{Version: 1, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(raw, data) {
+		t.Errorf("version 1 data was rewritten: %s", raw)
+	}
+}
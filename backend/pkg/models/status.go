@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ApplicationStatus is the lifecycle state of an application.
+type ApplicationStatus string
+
+const (
+	ApplicationStatusDraft       ApplicationStatus = "draft"
+	ApplicationStatusSubmitted   ApplicationStatus = "submitted"
+	ApplicationStatusUnderReview ApplicationStatus = "under_review"
+	ApplicationStatusApproved    ApplicationStatus = "approved"
+	ApplicationStatusRejected    ApplicationStatus = "rejected"
+	ApplicationStatusNeedsInfo   ApplicationStatus = "needs_info"
+	ApplicationStatusWithdrawn   ApplicationStatus = "withdrawn"
+)
+
+// ErrIllegalTransition is returned when an application cannot move between two states.
+var ErrIllegalTransition = errors.New("illegal status transition")
+
+// ErrReasonRequired is returned when a transition into a state that must be justified has no reason.
+var ErrReasonRequired = errors.New("reason required")
+
+// applicationTransitions lists, for every state, the states it can move to and the
+// permission the actor needs to make that move.
+var applicationTransitions = map[ApplicationStatus]map[ApplicationStatus]Permission{
+	ApplicationStatusDraft: {
+		ApplicationStatusSubmitted: PermissionApplicationOwn,
+	},
+	ApplicationStatusSubmitted: {
+		ApplicationStatusUnderReview: PermissionApplicationReview,
+		ApplicationStatusWithdrawn:   PermissionApplicationOwn,
+	},
+	ApplicationStatusUnderReview: {
+		ApplicationStatusApproved:  PermissionApplicationReview,
+		ApplicationStatusRejected:  PermissionApplicationReview,
+		ApplicationStatusNeedsInfo: PermissionApplicationReview,
+		ApplicationStatusWithdrawn: PermissionApplicationOwn,
+	},
+	ApplicationStatusNeedsInfo: {
+		ApplicationStatusSubmitted: PermissionApplicationOwn,
+		ApplicationStatusWithdrawn: PermissionApplicationOwn,
+	},
+	ApplicationStatusWithdrawn: {
+		ApplicationStatusSubmitted: PermissionApplicationOwn,
+	},
+}
+
+// IsValid reports whether the status is one of the known states.
+func (s ApplicationStatus) IsValid() bool {
+	switch s {
+	case ApplicationStatusDraft, ApplicationStatusSubmitted, ApplicationStatusUnderReview,
+		ApplicationStatusApproved, ApplicationStatusRejected, ApplicationStatusNeedsInfo, ApplicationStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// IsEditable reports whether the applicant may change the application in this state.
+func (s ApplicationStatus) IsEditable() bool {
+	return s == ApplicationStatusDraft || s == ApplicationStatusNeedsInfo || s == ApplicationStatusWithdrawn
+}
+
+// AcceptsDocuments reports whether the applicant may still replace documents in this state. Besides
+// the editable states this covers applications waiting for or under review, where rejected
+// documents can be replaced; decided applications are closed.
+func (s ApplicationStatus) AcceptsDocuments() bool {
+	return s.IsEditable() || s == ApplicationStatusSubmitted || s == ApplicationStatusUnderReview
+}
+
+// RequiresReason reports whether moving into this state must be justified with a reason.
+func (s ApplicationStatus) RequiresReason() bool {
+	return s == ApplicationStatusRejected || s == ApplicationStatusNeedsInfo
+}
+
+// ValidateTransition checks that an actor holding the given permission may move an application from one state to another.
+//
+// Parameters:
+// - from (ApplicationStatus): The current state.
+// - to (ApplicationStatus): The requested state.
+// - by (Permission): The permission the actor is acting under.
+//
+// Returns:
+// - error: An error wrapping ErrIllegalTransition, or nil if the transition is allowed.
+func ValidateTransition(from, to ApplicationStatus, by Permission) error {
+	required, ok := applicationTransitions[from][to]
+	if !ok {
+		return fmt.Errorf("%w: cannot move application from %q to %q", ErrIllegalTransition, from, to)
+	}
+	if required != by {
+		return fmt.Errorf("%w: moving application from %q to %q requires %q", ErrIllegalTransition, from, to, required)
+	}
+	return nil
+}
+
+// TransitionRequest is the payload used by reviewers to move an application to a new state
+type TransitionRequest struct {
+	Status ApplicationStatus `json:"status" binding:"required" example:"under_review"`
+	Reason string            `json:"reason" example:"Class XII certificate is unreadable"`
+}
+
+// ApplicationStatusHistory is an append-only record of one status change of an application
+type ApplicationStatusHistory struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	ApplicationID uint              `gorm:"not null;index" json:"application_id"`
+	FromStatus    ApplicationStatus `gorm:"type:varchar(20)" json:"from_status"`
+	ToStatus      ApplicationStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorID       uint              `gorm:"not null" json:"actor_id"`
+	Reason        string            `json:"reason,omitempty"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// ErrHistoryImmutable is returned when something tries to change or remove a history entry.
+var ErrHistoryImmutable = errors.New("application status history is append-only")
+
+func (h *ApplicationStatusHistory) BeforeUpdate(tx *gorm.DB) error {
+	return ErrHistoryImmutable
+}
+
+func (h *ApplicationStatusHistory) BeforeDelete(tx *gorm.DB) error {
+	return ErrHistoryImmutable
+}
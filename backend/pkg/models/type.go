@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // PaginationMeta contains metadata for paginated responses
 type PaginationMeta struct {
 	ResourceCount int    `json:"resource_count" example:"200"`
@@ -36,9 +38,24 @@ func (p PaginationInput) GetLimit() int64 {
 
 // ErrorResponse for API error output
 type ErrorResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// FieldError describes one invalid input field. Field is the JSON path of the field.
+type FieldError struct {
+	Field   string `json:"field" example:"addresses[0].type"`
+	Message string `json:"message" example:"must be permanent or current"`
+}
+
+// JSONPatchOperation is one operation of a JSON Patch (RFC 6902) document.
+type JSONPatchOperation struct {
+	Op    string          `json:"op" example:"replace"` // add, remove, replace, move, copy or test
+	Path  string          `json:"path" example:"/nationality"`
+	From  string          `json:"from,omitempty"` // source path of move and copy
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
 }
 
 // SuccessResponse for success output
@@ -55,3 +72,11 @@ type SchemeResponse struct {
 	Data    interface{}     `json:"data,omitempty"`
 	Meta    *PaginationMeta `json:"meta,omitempty"`
 }
+
+// ApplicationResponse for paginated applications
+type ApplicationResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    interface{}     `json:"data,omitempty"`
+	Meta    *PaginationMeta `json:"meta,omitempty"`
+}
@@ -0,0 +1,62 @@
+// Package scheduler runs the periodic background jobs of the server.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db"
+	"github.com/ChayanDass/beneficiary-manager/pkg/utils"
+)
+
+// DefaultInterval is how often scheme statuses are advanced unless SCHEME_STATUS_INTERVAL is set.
+const DefaultInterval = time.Minute
+
+// Setup starts the scheme lifecycle job in the background. SCHEME_STATUS_INTERVAL sets how
+// often it runs as a Go duration, e.g. "30s"; "0" disables it, for example when another
+// replica already runs the job.
+func Setup() {
+	interval := DefaultInterval
+	if value := os.Getenv("SCHEME_STATUS_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid SCHEME_STATUS_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+	if interval == 0 {
+		log.Printf("SCHEME_STATUS_INTERVAL is 0, scheme statuses will not be updated automatically")
+		return
+	}
+
+	go Run(context.Background(), interval)
+}
+
+// Run advances scheme statuses immediately and then at every interval until ctx is done.
+func Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		AdvanceSchemes(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdvanceSchemes opens and closes schemes whose start or end date has passed, logging the result.
+func AdvanceSchemes(now time.Time) {
+	opened, closed, err := utils.AdvanceSchemeStatuses(db.DB, now)
+	if err != nil {
+		log.Printf("Failed to update scheme statuses: %v", err)
+		return
+	}
+	if opened > 0 || closed > 0 {
+		log.Printf("Scheme statuses updated: %d opened, %d closed", opened, closed)
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LinkTTL is how long a signed download link stays valid. Links are signed whenever a
+	// document is returned, so they are never stored.
+	LinkTTL = time.Hour
+	// MinSigningSecretLength is the minimum length of STORAGE_SIGNING_SECRET.
+	MinSigningSecretLength = 32
+
+	filesPath         = "/api/v1/files/"
+	placeholderSecret = "change-me"
+)
+
+var (
+	// ErrInvalidLink is returned when a download link is expired or its signature does not match.
+	ErrInvalidLink = errors.New("invalid or expired file link")
+	// ErrWeakSigningSecret is returned when STORAGE_SIGNING_SECRET is the placeholder or too short.
+	ErrWeakSigningSecret = errors.New("weak signing secret")
+)
+
+// CheckSigningSecret verifies that STORAGE_SIGNING_SECRET is set to a usable value.
+//
+// Returns:
+// - error: An error wrapping ErrWeakSigningSecret for the placeholder or a short secret, another
+// error if it is not set, or nil.
+func CheckSigningSecret() error {
+	_, err := signingSecret()
+	return err
+}
+
+func signingSecret() ([]byte, error) {
+	secret := os.Getenv("STORAGE_SIGNING_SECRET")
+	switch {
+	case secret == "":
+		return nil, errors.New("STORAGE_SIGNING_SECRET is not configured")
+	case secret == placeholderSecret:
+		return nil, fmt.Errorf("%w: STORAGE_SIGNING_SECRET is still set to the placeholder value", ErrWeakSigningSecret)
+	case len(secret) < MinSigningSecretLength:
+		return nil, fmt.Errorf("%w: STORAGE_SIGNING_SECRET must be at least %d characters", ErrWeakSigningSecret, MinSigningSecretLength)
+	}
+	return []byte(secret), nil
+}
+
+func linkSignature(secret []byte, key string, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FilePath returns the unsigned path under which a stored object is served. It is stable, so it
+// is what documents store; SignURL turns it into a working link.
+func FilePath(key string) string {
+	return filesPath + key
+}
+
+// IsLinkTo reports whether a link, signed or not, points at the stored object with the given key.
+func IsLinkTo(link, key string) bool {
+	u, err := url.Parse(link)
+	return err == nil && key != "" && strings.HasSuffix(u.Path, FilePath(key))
+}
+
+// SignURL returns a download link for a stored object that is valid for LinkTTL.
+// The link is absolute when PUBLIC_BASE_URL is set.
+//
+// Parameters:
+// - key (string): The storage key of the object.
+//
+// Returns:
+// - string: The signed download link.
+// - error: An error if no usable signing secret is configured.
+func SignURL(key string) (string, error) {
+	secret, err := signingSecret()
+	if err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(LinkTTL).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", linkSignature(secret, key, expires))
+	return strings.TrimSuffix(os.Getenv("PUBLIC_BASE_URL"), "/") + FilePath(key) + "?" + query.Encode(), nil
+}
+
+// VerifyLink checks the expiry and signature of a download link.
+//
+// Parameters:
+// - key (string): The storage key from the link path.
+// - expires (string): The expires query parameter.
+// - signature (string): The signature query parameter.
+//
+// Returns:
+// - error: ErrInvalidLink if the link is expired or tampered with.
+func VerifyLink(key, expires, signature string) error {
+	secret, err := signingSecret()
+	if err != nil {
+		return err
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidLink
+	}
+	if !hmac.Equal([]byte(signature), []byte(linkSignature(secret, key, exp))) {
+		return ErrInvalidLink
+	}
+	return nil
+}
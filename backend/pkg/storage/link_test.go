@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSigningSecret = "0123456789abcdef0123456789abcdef"
+
+func TestCheckSigningSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		weak   bool
+		ok     bool
+	}{
+		{"empty", "", false, false},
+		{"placeholder", "change-me", true, false},
+		{"too short", strings.Repeat("x", MinSigningSecretLength-1), true, false},
+		{"long enough", testSigningSecret, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STORAGE_SIGNING_SECRET", tt.secret)
+			err := CheckSigningSecret()
+			if (err == nil) != tt.ok || errors.Is(err, ErrWeakSigningSecret) != tt.weak {
+				t.Errorf("CheckSigningSecret() = %v, want ok %v, weak %v", err, tt.ok, tt.weak)
+			}
+		})
+	}
+}
+
+// linkParams splits a signed link into its key, expiry and signature.
+func linkParams(t *testing.T, link string) (string, string, string) {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, ok := strings.CutPrefix(u.Path, filesPath)
+	if !ok {
+		t.Fatalf("link %s is not below %s", link, filesPath)
+	}
+	return key, u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestSignURLRoundTrip(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", testSigningSecret)
+	t.Setenv("PUBLIC_BASE_URL", "")
+
+	link, err := SignURL("applications/7/aadhar_card-abc.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, expires, signature := linkParams(t, link)
+	if key != "applications/7/aadhar_card-abc.pdf" {
+		t.Errorf("key = %q", key)
+	}
+	exp, _ := strconv.ParseInt(expires, 10, 64)
+	if ttl := time.Until(time.Unix(exp, 0)); ttl <= 0 || ttl > LinkTTL {
+		t.Errorf("link expires in %v, want within %v", ttl, LinkTTL)
+	}
+	if err := VerifyLink(key, expires, signature); err != nil {
+		t.Errorf("VerifyLink rejected a fresh link: %v", err)
+	}
+	if !IsLinkTo(link, key) || IsLinkTo(link, "applications/7/other.pdf") {
+		t.Error("IsLinkTo does not recognise the link")
+	}
+}
+
+func TestSignURLAbsolute(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", testSigningSecret)
+	t.Setenv("PUBLIC_BASE_URL", "https://benefits.example.org/")
+
+	link, err := SignURL("a/b.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link, "https://benefits.example.org/api/v1/files/a/b.pdf?") {
+		t.Errorf("link = %s", link)
+	}
+	if !IsLinkTo(link, "a/b.pdf") {
+		t.Error("IsLinkTo does not recognise an absolute link")
+	}
+}
+
+func TestVerifyLinkRejects(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", testSigningSecret)
+	t.Setenv("PUBLIC_BASE_URL", "")
+
+	link, err := SignURL("a/b.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, expires, signature := linkParams(t, link)
+	past := time.Now().Add(-time.Minute).Unix()
+	expired := linkSignature([]byte(testSigningSecret), key, past)
+	later, _ := strconv.ParseInt(expires, 10, 64)
+
+	tests := []struct {
+		name, key, expires, signature string
+	}{
+		{"expired", key, strconv.FormatInt(past, 10), expired},
+		{"other key", "a/c.pdf", expires, signature},
+		{"extended expiry", key, strconv.FormatInt(later+3600, 10), signature},
+		{"tampered signature", key, expires, strings.Repeat("0", len(signature))},
+		{"missing signature", key, expires, ""},
+		{"bad expiry", key, "soon", signature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyLink(tt.key, tt.expires, tt.signature); !errors.Is(err, ErrInvalidLink) {
+				t.Errorf("VerifyLink = %v, want ErrInvalidLink", err)
+			}
+		})
+	}
+
+	t.Run("other secret", func(t *testing.T) {
+		t.Setenv("STORAGE_SIGNING_SECRET", strings.Repeat("y", 32))
+		if err := VerifyLink(key, expires, signature); !errors.Is(err, ErrInvalidLink) {
+			t.Errorf("VerifyLink = %v, want ErrInvalidLink", err)
+		}
+	})
+}
+
+func TestSignURLWithoutSecret(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", "")
+	if _, err := SignURL("a/b.pdf"); err == nil {
+		t.Error("SignURL signed a link without a secret")
+	}
+	if err := VerifyLink("a/b.pdf", "1", "x"); err == nil || errors.Is(err, ErrInvalidLink) {
+		t.Errorf("VerifyLink = %v, want a configuration error", err)
+	}
+}
+
+func TestSignURLWithWeakSecret(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_SECRET", "change-me")
+	if _, err := SignURL("a/b.pdf"); !errors.Is(err, ErrWeakSigningSecret) {
+		t.Errorf("SignURL = %v, want ErrWeakSigningSecret", err)
+	}
+	if err := VerifyLink("a/b.pdf", "1", "x"); !errors.Is(err, ErrWeakSigningSecret) {
+		t.Errorf("VerifyLink = %v, want ErrWeakSigningSecret", err)
+	}
+}
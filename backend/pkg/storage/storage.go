@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+// ErrNotFound is returned when no object exists under a key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage is implemented by every file storage driver.
+type Storage interface {
+	// Put stores size bytes read from r under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. The caller must close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Store is the storage driver used by the application.
+var Store Storage
+
+// Setup initializes Store from the STORAGE_DRIVER environment variable ("local" or "s3").
+// It stops the server if STORAGE_SIGNING_SECRET is missing or weak.
+func Setup() {
+	if err := CheckSigningSecret(); err != nil {
+		log.Fatalf("Refusing to start: %v. Generate one with `openssl rand -hex 32`.", err)
+	}
+
+	driver := getEnv("STORAGE_DRIVER", "local")
+
+	var err error
+	switch driver {
+	case "local":
+		Store, err = NewLocalStorage(getEnv("STORAGE_LOCAL_PATH", "./uploads"))
+	case "s3":
+		useSSL, _ := strconv.ParseBool(getEnv("S3_USE_SSL", "true"))
+		Store, err = NewS3Storage(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			UseSSL:    useSSL,
+		})
+	default:
+		err = fmt.Errorf("unknown storage driver %q", driver)
+	}
+	if err != nil {
+		log.Fatalf("Failed to set up file storage: %v", err)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
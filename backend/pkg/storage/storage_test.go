@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// exerciseStorage runs the behaviour every driver must share against s.
+func exerciseStorage(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+	content := []byte("%PDF-1.4 test document")
+
+	if _, err := s.Get(ctx, "applications/1/missing.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing object = %v, want ErrNotFound", err)
+	}
+
+	key := "applications/1/aadhar_card-0123.pdf"
+	if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	r, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get returned %q, want %q", got, content)
+	}
+
+	replacement := []byte("%PDF-1.4 replacement")
+	if err := s.Put(ctx, key, bytes.NewReader(replacement), int64(len(replacement)), "application/pdf"); err != nil {
+		t.Fatalf("Put over an existing object: %v", err)
+	}
+	r, err = s.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(got, replacement) {
+		t.Errorf("Get after overwrite returned %q", got)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing object = %v, want nil", err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStorage(t, s)
+}
+
+func TestLocalStorageRejectsEscapingKeys(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../outside.pdf", "a/../../outside.pdf", ""} {
+		if err := s.Put(context.Background(), key, strings.NewReader("x"), 1, "application/pdf"); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+	}
+}
+
+// fakeS3 is an in-memory stand-in for the parts of the S3 API the driver uses.
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if key == "" {
+		// Bucket existence check
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err == nil && strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body, err = decodeAWSChunked(body)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodHead, http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			if r.Method == http.MethodGet {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAWSChunked strips the chunk headers of a streaming signed upload; signatures are not checked.
+func decodeAWSChunked(body []byte) ([]byte, error) {
+	var out []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, errors.New("truncated chunk header")
+		}
+		sizeHex, _, _ := strings.Cut(string(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || int64(len(rest)) < size+2 {
+			return nil, errors.New("invalid chunk")
+		}
+		if size == 0 {
+			return out, nil
+		}
+		out = append(out, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, S3Config) {
+	t.Helper()
+	fake := &fakeS3{bucket: "documents", objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Bucket:    fake.bucket,
+		Region:    "us-east-1",
+		AccessKey: "test",
+		SecretKey: "test-secret",
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake, cfg := newFakeS3(t)
+	s, err := NewS3Storage(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStorage(t, s)
+
+	content := []byte("\x89PNG image")
+	if err := s.Put(context.Background(), "a/b.png", bytes.NewReader(content), int64(len(content)), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.types["a/b.png"] != "image/png" {
+		t.Errorf("stored content type = %q, want image/png", fake.types["a/b.png"])
+	}
+}
+
+func TestS3StorageMissingBucket(t *testing.T) {
+	_, cfg := newFakeS3(t)
+	cfg.Bucket = "other"
+	if _, err := NewS3Storage(cfg); err == nil {
+		t.Fatal("NewS3Storage accepted a bucket that does not exist")
+	}
+	if _, err := NewS3Storage(S3Config{}); err == nil {
+		t.Fatal("NewS3Storage accepted an empty configuration")
+	}
+}
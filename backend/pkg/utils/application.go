@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrApplicationExists is returned when a user already has an application for a scheme.
+var ErrApplicationExists = errors.New("application already exists")
+
+// ErrApplicationNotEditable is returned when an applicant changes an application that is not a draft.
+var ErrApplicationNotEditable = errors.New("application cannot be modified in its current state")
+
+// SubmissionError describes why an application cannot be submitted.
+type SubmissionError struct {
+	Message string
+	Err     error
+	Details interface{}
+}
+
+func (e *SubmissionError) Error() string { return e.Err.Error() }
+
+func (e *SubmissionError) Unwrap() error { return e.Err }
+
+// PreloadApplicationDetails preloads everything needed to validate and display an application.
+func PreloadApplicationDetails(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("User").
+		Preload("Scheme").
+		Preload("Scheme.Eligibility").
+		Preload("Scheme.Eligibility.DocumentMappings").
+		Preload("Scheme.Eligibility.DocumentMappings.Document").
+		Preload("StudentProfile").
+		Preload("StudentProfile.Documents").
+		Preload("StudentProfile.EducationHistory").
+		Preload("StudentProfile.Addresses")
+}
+
+// InitApplication creates a draft application for a scheme. Drafts use the applicant's saved
+// profile, so the application starts with everything the applicant entered before.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - userID (uint): The ID of the applicant.
+// - schemeID (uint): The ID of the scheme.
+// - profile (*models.StudentProfile): Profile fields that replace the saved ones, or nil to keep them.
+//
+// Returns:
+// - *models.Application: The created application.
+// - error: ErrApplicationExists, gorm.ErrRecordNotFound if the scheme does not exist, an error wrapping
+// ErrSchemeNotOpen, or a database error.
+func InitApplication(tx *gorm.DB, userID, schemeID uint, profile *models.StudentProfile) (*models.Application, error) {
+	var existing models.Application
+	if err := tx.
+		Where("user_id = ? AND scheme_id = ? ", userID, schemeID).
+		First(&existing).Error; err == nil {
+		return nil, ErrApplicationExists
+	}
+
+	var scheme models.Scheme
+	if err := tx.First(&scheme, schemeID).Error; err != nil {
+		return nil, err
+	}
+	if err := CheckSchemeOpen(&scheme, time.Now()); err != nil {
+		return nil, err
+	}
+
+	application := models.Application{
+		UserID:   userID,
+		SchemeID: schemeID,
+		IsDraft:  true,
+		Status:   models.ApplicationStatusDraft,
+	}
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		student, err := EnsureApplicantProfile(tx, userID)
+		if err != nil {
+			return err
+		}
+		application.StudentProfileID = student.ID
+		if profile != nil {
+			if err := OverwriteStudentProfileFields(tx, student.ID, profile); err != nil {
+				return err
+			}
+			student = &models.StudentProfile{}
+			if err := PreloadStudentProfile(tx).First(student, application.StudentProfileID).Error; err != nil {
+				return err
+			}
+		}
+
+		application.StudentProfile = *student
+		if err := tx.Omit("User", "Scheme", "StudentProfile").Create(&application).Error; err != nil {
+			return err
+		}
+		return RecordStatusChange(tx, application.ID, "", models.ApplicationStatusDraft, userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// ModifyApplication updates the profile, documents, addresses and education history of an
+// applicant's draft application. Everything is stored in one transaction, so a failing step
+// leaves the application unchanged.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - userID (uint): The ID of the applicant.
+// - applicationID (string): The ID of the application.
+// - input (models.StudentProfileInput): The submitted profile data.
+//
+// Returns:
+// - *models.Application: The updated application, reloaded with PreloadApplicationDetails.
+// - error: ValidationErrors if the input is invalid, gorm.ErrRecordNotFound if the applicant has no
+// such application, an error wrapping ErrApplicationNotEditable, or a database error.
+func ModifyApplication(tx *gorm.DB, userID uint, applicationID string, input models.StudentProfileInput) (*models.Application, error) {
+	var application models.Application
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		editable, err := FindEditableApplication(tx, userID, applicationID)
+		if err != nil {
+			return err
+		}
+
+		var profile models.StudentProfile
+		if err := tx.First(&profile, editable.StudentProfileID).Error; err != nil {
+			return fmt.Errorf("failed to load student profile: %w", err)
+		}
+		if err := UpdateStudentProfile(tx, &profile, input); err != nil {
+			return err
+		}
+
+		application = models.Application{}
+		return PreloadApplicationDetails(tx).
+			Where("user_id = ? AND id = ?", userID, applicationID).
+			First(&application).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// PatchApplication applies a JSON Merge Patch or JSON Patch to the profile of an applicant's
+// draft application. The patch works on the application's profile in the StudentProfileInput
+// shape, as returned by StudentProfileToInput; removing a member or setting it to null clears it.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - userID (uint): The ID of the applicant.
+// - applicationID (string): The ID of the application.
+// - patch ([]byte): The patch document.
+// - format (PatchFormat): The kind of patch.
+//
+// Returns:
+// - *models.Application: The updated application, reloaded with PreloadApplicationDetails.
+// - []models.UploadDocument: The documents the patch removed.
+// - error: An error wrapping ErrInvalidPatch or ErrPatchTestFailed, ValidationErrors if the patched
+// profile is invalid, gorm.ErrRecordNotFound if the applicant has no such application, an error
+// wrapping ErrApplicationNotEditable, or a database error.
+func PatchApplication(tx *gorm.DB, userID uint, applicationID string, patch []byte, format PatchFormat) (*models.Application, []models.UploadDocument, error) {
+	var application models.Application
+	var removed []models.UploadDocument
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		editable, err := FindEditableApplication(tx, userID, applicationID)
+		if err != nil {
+			return err
+		}
+
+		var profile models.StudentProfile
+		if err := PreloadStudentProfile(tx).First(&profile, editable.StudentProfileID).Error; err != nil {
+			return fmt.Errorf("failed to load student profile: %w", err)
+		}
+		doc, err := json.Marshal(StudentProfileToInput(&profile))
+		if err != nil {
+			return err
+		}
+		if doc, err = ApplyPatch(doc, patch, format); err != nil {
+			return err
+		}
+		var input models.StudentProfileInput
+		if err := DecodePatchedInput(doc, &input); err != nil {
+			return err
+		}
+		if removed, err = ReplaceStudentProfile(tx, &profile, input); err != nil {
+			return err
+		}
+		if err := RefreshApplicationVerified(tx, editable); err != nil {
+			return err
+		}
+
+		return PreloadApplicationDetails(tx).First(&application, editable.ID).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &application, removed, nil
+}
+
+// SubmitApplication validates an application and moves it to submitted.
+// It checks that the scheme is open, completeness, the scheme's mandatory documents and the eligibility criteria.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - app (*models.Application): The application, loaded with PreloadApplicationDetails.
+// - actorID (uint): The ID of the user submitting.
+//
+// Returns:
+// - error: A *SubmissionError if validation fails, an error wrapping models.ErrIllegalTransition
+// or ErrSchemeNotOpen, or a database error.
+func SubmitApplication(tx *gorm.DB, app *models.Application, actorID uint) error {
+	if err := CanTransitionApplication(app, models.ApplicationStatusSubmitted, models.PermissionApplicationOwn); err != nil {
+		return err
+	}
+	if err := CheckSchemeOpen(&app.Scheme, time.Now()); err != nil {
+		return err
+	}
+
+	// Completeness is checked in full for non-draft applications
+	isDraft := app.IsDraft
+	app.IsDraft = false
+	err := CheckApplicationCompleteness(app)
+	app.IsDraft = isDraft
+	if err != nil {
+		return &SubmissionError{Message: "Application is incomplete", Err: err}
+	}
+
+	docReport, err := CheckRequiredDocuments(tx, app)
+	if err != nil {
+		return err
+	}
+	if !docReport.OK() {
+		return &SubmissionError{
+			Message: "Application documents do not match the scheme requirements",
+			Err:     errors.New("missing or unknown documents"),
+			Details: docReport,
+		}
+	}
+
+	if report := EvaluateEligibility(&app.StudentProfile, &app.Scheme, time.Now()); !report.Eligible {
+		return &SubmissionError{
+			Message: "Applicant is not eligible for this scheme",
+			Err:     errors.New("eligibility criteria not met"),
+			Details: report,
+		}
+	}
+
+	return TransitionApplication(tx, app, models.ApplicationStatusSubmitted, models.PermissionApplicationOwn, actorID, "")
+}
@@ -0,0 +1,37 @@
+package utils
+
+import "strings"
+
+// csvFormulaPrefixes are the leading characters that make spreadsheet applications evaluate a cell.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// EscapeCSVCell prefixes a cell that a spreadsheet would treat as a formula with a single quote,
+// so exported user data cannot run formulas when the file is opened.
+//
+// Parameters:
+// - value (string): The cell value.
+//
+// Returns:
+// - string: The value, prefixed with ' if it starts with =, +, -, @, a tab or a carriage return.
+func EscapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// UnescapeCSVCell reverses EscapeCSVCell.
+func UnescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// EscapeCSVRow applies EscapeCSVCell to every cell of a row in place and returns the row.
+func EscapeCSVRow(row []string) []string {
+	for i, cell := range row {
+		row[i] = EscapeCSVCell(cell)
+	}
+	return row
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Merit Scholarship", "Merit Scholarship"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"a=b", "a=b"},
+		{"'quoted", "'quoted"},
+	}
+	for _, tt := range tests {
+		if got := EscapeCSVCell(tt.value); got != tt.want {
+			t.Errorf("EscapeCSVCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if back := UnescapeCSVCell(tt.want); back != tt.value {
+			t.Errorf("UnescapeCSVCell(%q) = %q, want %q", tt.want, back, tt.value)
+		}
+	}
+}
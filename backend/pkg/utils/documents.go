@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// CheckRequiredDocuments compares the documents uploaded for an application with the documents its scheme requires.
+// Documents are matched by DocumentsRequired.Name.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - app (*models.Application): The application, with StudentProfile.Documents and
+// Scheme.Eligibility.DocumentMappings.Document populated.
+//
+// Returns:
+// - models.DocumentCheckReport: The mandatory documents that are missing and the uploaded names that are not known document types.
+// - error: An error if the known document types cannot be loaded.
+func CheckRequiredDocuments(tx *gorm.DB, app *models.Application) (models.DocumentCheckReport, error) {
+	report := models.DocumentCheckReport{Missing: []string{}, Unknown: []string{}}
+
+	var known []string
+	if err := tx.Model(&models.DocumentsRequired{}).Pluck("name", &known).Error; err != nil {
+		return report, fmt.Errorf("error loading document types: %w", err)
+	}
+	knownSet := make(map[string]bool, len(known))
+	for _, name := range known {
+		knownSet[name] = true
+	}
+
+	uploaded := make(map[string]bool, len(app.StudentProfile.Documents))
+	for _, doc := range app.StudentProfile.Documents {
+		if !knownSet[doc.Name] {
+			report.Unknown = append(report.Unknown, doc.Name)
+			continue
+		}
+		// Rejected documents have to be re-uploaded before they count
+		if doc.URL != "" && doc.VerificationStatus != models.DocumentRejected {
+			uploaded[doc.Name] = true
+		}
+	}
+
+	for _, mapping := range app.Scheme.Eligibility.DocumentMappings {
+		if mapping.IsMandatory && !uploaded[mapping.Document.Name] {
+			report.Missing = append(report.Missing, mapping.Document.Name)
+		}
+	}
+	return report, nil
+}
+
+// RefreshApplicationVerified derives Application.Verified from the verification status of its documents.
+// An application is verified once it has documents and every one of them is verified.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - app (*models.Application): The application to update.
+//
+// Returns:
+// - error: An error if the documents cannot be counted or the application cannot be saved.
+func RefreshApplicationVerified(tx *gorm.DB, app *models.Application) error {
+	var total, verified int64
+	if err := tx.Model(&models.UploadDocument{}).
+		Where("student_id = ?", app.StudentProfileID).
+		Count(&total).Error; err != nil {
+		return fmt.Errorf("error counting documents: %w", err)
+	}
+	if err := tx.Model(&models.UploadDocument{}).
+		Where("student_id = ? AND verification_status = ?", app.StudentProfileID, models.DocumentVerified).
+		Count(&verified).Error; err != nil {
+		return fmt.Errorf("error counting verified documents: %w", err)
+	}
+
+	app.Verified = total > 0 && verified == total
+	if err := tx.Model(app).Update("verified", app.Verified).Error; err != nil {
+		return fmt.Errorf("failed to update application verification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+// Criterion names used in eligibility reports
+const (
+	CriterionGender        = "gender"
+	CriterionAgeMin        = "age_min"
+	CriterionAgeMax        = "age_max"
+	CriterionIncome        = "income_limit"
+	CriterionQualification = "academic_qualification"
+	CriterionCategory      = "category"
+)
+
+// qualificationRank orders academic qualifications from lowest to highest.
+var qualificationRank = map[models.AcademicQualification]int{
+	models.AcademicQualificationNone:         0,
+	models.AcademicQualificationClassX:       1,
+	models.AcademicQualificationClassXII:     2,
+	models.AcademicQualificationDiploma:      3,
+	models.AcademicQualificationGraduate:     4,
+	models.AcademicQualificationPostGraduate: 5,
+}
+
+// AgeOn returns the age in completed years of someone born on dob at the given time.
+func AgeOn(dob, at time.Time) int {
+	age := at.Year() - dob.Year()
+	if at.Month() < dob.Month() || (at.Month() == dob.Month() && at.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
+
+// EvaluateEligibility checks a student profile against a scheme's eligibility criteria.
+// Criteria left empty or zero on the eligibility are not evaluated.
+//
+// Parameters:
+// - profile (*models.StudentProfile): The applicant's profile.
+// - scheme (*models.Scheme): The scheme, with Eligibility populated.
+// - at (time.Time): The moment at which age is computed.
+//
+// Returns:
+// - models.EligibilityReport: The overall verdict and the result for each evaluated criterion.
+func EvaluateEligibility(profile *models.StudentProfile, scheme *models.Scheme, at time.Time) models.EligibilityReport {
+	criteria := &scheme.Eligibility
+	results := []models.CriterionResult{}
+
+	if criteria.Gender != "" {
+		results = append(results, matchCriterion(CriterionGender, string(criteria.Gender), profile.Gender))
+	}
+
+	if criteria.AgeMin > 0 || criteria.AgeMax > 0 {
+		if profile.DateOfBirth == nil {
+			if criteria.AgeMin > 0 {
+				results = append(results, missingCriterion(CriterionAgeMin, fmt.Sprint(criteria.AgeMin), "date of birth is missing"))
+			}
+			if criteria.AgeMax > 0 {
+				results = append(results, missingCriterion(CriterionAgeMax, fmt.Sprint(criteria.AgeMax), "date of birth is missing"))
+			}
+		} else {
+			age := AgeOn(*profile.DateOfBirth, at)
+			if criteria.AgeMin > 0 {
+				r := models.CriterionResult{Criterion: CriterionAgeMin, Required: fmt.Sprint(criteria.AgeMin), Actual: fmt.Sprint(age), Passed: age >= criteria.AgeMin}
+				if !r.Passed {
+					r.Reason = fmt.Sprintf("age %d is below the minimum of %d", age, criteria.AgeMin)
+				}
+				results = append(results, r)
+			}
+			if criteria.AgeMax > 0 {
+				r := models.CriterionResult{Criterion: CriterionAgeMax, Required: fmt.Sprint(criteria.AgeMax), Actual: fmt.Sprint(age), Passed: age <= criteria.AgeMax}
+				if !r.Passed {
+					r.Reason = fmt.Sprintf("age %d is above the maximum of %d", age, criteria.AgeMax)
+				}
+				results = append(results, r)
+			}
+		}
+	}
+
+	if criteria.IncomeLimit > 0 {
+		r := models.CriterionResult{
+			Criterion: CriterionIncome,
+			Required:  fmt.Sprintf("%.2f", criteria.IncomeLimit),
+			Actual:    fmt.Sprintf("%.2f", profile.Income),
+			Passed:    profile.Income <= criteria.IncomeLimit,
+		}
+		if !r.Passed {
+			r.Reason = fmt.Sprintf("income %.2f exceeds the limit of %.2f", profile.Income, criteria.IncomeLimit)
+		}
+		results = append(results, r)
+	}
+
+	if criteria.AcademicQualification != "" && criteria.AcademicQualification != models.AcademicQualificationNone {
+		required := qualificationRank[criteria.AcademicQualification]
+		r := models.CriterionResult{
+			Criterion: CriterionQualification,
+			Required:  string(criteria.AcademicQualification),
+			Actual:    profile.Qualification,
+		}
+		actual, known := qualificationRank[models.AcademicQualification(profile.Qualification)]
+		switch {
+		case profile.Qualification == "":
+			r.Reason = "qualification is missing"
+		case !known:
+			r.Reason = fmt.Sprintf("unknown qualification %q", profile.Qualification)
+		case actual < required:
+			r.Reason = fmt.Sprintf("qualification %s is below the required %s", profile.Qualification, criteria.AcademicQualification)
+		default:
+			r.Passed = true
+		}
+		results = append(results, r)
+	}
+
+	if criteria.Category != "" {
+		results = append(results, matchCriterion(CriterionCategory, string(criteria.Category), profile.Category))
+	}
+
+	report := models.EligibilityReport{SchemeID: scheme.ID, Eligible: true, Criteria: results}
+	for _, r := range results {
+		if !r.Passed {
+			report.Eligible = false
+			break
+		}
+	}
+	return report
+}
+
+func matchCriterion(name, required, actual string) models.CriterionResult {
+	r := models.CriterionResult{Criterion: name, Required: required, Actual: actual}
+	switch {
+	case actual == "":
+		r.Reason = name + " is missing"
+	case !strings.EqualFold(required, actual):
+		r.Reason = fmt.Sprintf("%s %s does not match the required %s", name, actual, required)
+	default:
+		r.Passed = true
+	}
+	return r
+}
+
+func missingCriterion(name, required, reason string) models.CriterionResult {
+	return models.CriterionResult{Criterion: name, Required: required, Reason: reason}
+}
+
+// nearMissMaxFailures is the number of failed criteria up to which an ineligible scheme is reported as a near miss.
+const nearMissMaxFailures = 1
+
+// RecommendSchemes evaluates a profile against every scheme and splits them into eligible schemes and near misses.
+// Eligible schemes are ranked by amount (highest first) and then by closing date (soonest first).
+//
+// Parameters:
+// - profile (*models.StudentProfile): The applicant's profile.
+// - schemes ([]models.Scheme): The candidate schemes, with Eligibility populated.
+// - at (time.Time): The moment at which age is computed.
+//
+// Returns:
+// - models.SchemeRecommendations: The ranked eligible schemes and the near misses with their reasons.
+func RecommendSchemes(profile *models.StudentProfile, schemes []models.Scheme, at time.Time) models.SchemeRecommendations {
+	recs := models.SchemeRecommendations{
+		Eligible:   []models.Scheme{},
+		NearMisses: []models.NearMissScheme{},
+	}
+
+	for i := range schemes {
+		report := EvaluateEligibility(profile, &schemes[i], at)
+		if report.Eligible {
+			recs.Eligible = append(recs.Eligible, schemes[i])
+			continue
+		}
+
+		var reasons []string
+		for _, r := range report.Criteria {
+			if !r.Passed {
+				reasons = append(reasons, r.Reason)
+			}
+		}
+		if len(reasons) <= nearMissMaxFailures {
+			recs.NearMisses = append(recs.NearMisses, models.NearMissScheme{Scheme: schemes[i], Reasons: reasons})
+		}
+	}
+
+	sort.SliceStable(recs.Eligible, func(i, j int) bool {
+		a, b := recs.Eligible[i], recs.Eligible[j]
+		if a.Amount != b.Amount {
+			return a.Amount > b.Amount
+		}
+		return a.EndDate.Before(b.EndDate)
+	})
+	return recs
+}
@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+// evaluationTime is the moment eligibility is evaluated at in these tests.
+var evaluationTime = time.Date(2025, time.June, 15, 10, 0, 0, 0, time.UTC)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func birthDate(year int, month time.Month, day int) *time.Time {
+	dob := date(year, month, day)
+	return &dob
+}
+
+func TestAgeOn(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		want int
+	}{
+		{"day before birthday", date(2005, time.June, 16), 19},
+		{"on birthday", date(2005, time.June, 15), 20},
+		{"day after birthday", date(2005, time.June, 14), 20},
+		{"later month", date(2005, time.July, 1), 19},
+		{"earlier month", date(2005, time.May, 31), 20},
+		{"leap day in a common year", date(2004, time.February, 29), 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgeOn(tt.dob, evaluationTime); got != tt.want {
+				t.Errorf("AgeOn(%s) = %d, want %d", tt.dob.Format(time.DateOnly), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEligibilityCriteria(t *testing.T) {
+	tests := []struct {
+		name      string
+		criteria  models.Eligibility
+		profile   models.StudentProfile
+		criterion string
+		passed    bool
+		reason    string
+	}{
+		{"gender matches", models.Eligibility{Gender: models.GenderFemale},
+			models.StudentProfile{Gender: "Female"}, CriterionGender, true, ""},
+		{"gender matches ignoring case", models.Eligibility{Gender: models.GenderFemale},
+			models.StudentProfile{Gender: "female"}, CriterionGender, true, ""},
+		{"gender differs", models.Eligibility{Gender: models.GenderFemale},
+			models.StudentProfile{Gender: "Male"}, CriterionGender, false, "gender Male does not match the required Female"},
+		{"gender missing", models.Eligibility{Gender: models.GenderFemale},
+			models.StudentProfile{}, CriterionGender, false, "gender is missing"},
+
+		{"minimum age reached on the birthday", models.Eligibility{AgeMin: 18},
+			models.StudentProfile{DateOfBirth: birthDate(2007, time.June, 15)}, CriterionAgeMin, true, ""},
+		{"minimum age one day short", models.Eligibility{AgeMin: 18},
+			models.StudentProfile{DateOfBirth: birthDate(2007, time.June, 16)}, CriterionAgeMin, false, "age 17 is below the minimum of 18"},
+		{"maximum age on the day before the birthday", models.Eligibility{AgeMax: 25},
+			models.StudentProfile{DateOfBirth: birthDate(1999, time.June, 16)}, CriterionAgeMax, true, ""},
+		{"maximum age exceeded on the birthday", models.Eligibility{AgeMax: 25},
+			models.StudentProfile{DateOfBirth: birthDate(1999, time.June, 15)}, CriterionAgeMax, false, "age 26 is above the maximum of 25"},
+		{"minimum age without date of birth", models.Eligibility{AgeMin: 18},
+			models.StudentProfile{}, CriterionAgeMin, false, "date of birth is missing"},
+		{"maximum age without date of birth", models.Eligibility{AgeMax: 25},
+			models.StudentProfile{}, CriterionAgeMax, false, "date of birth is missing"},
+
+		{"income below the limit", models.Eligibility{IncomeLimit: 250000},
+			models.StudentProfile{Income: 100000}, CriterionIncome, true, ""},
+		{"income equal to the limit", models.Eligibility{IncomeLimit: 250000},
+			models.StudentProfile{Income: 250000}, CriterionIncome, true, ""},
+		{"income above the limit", models.Eligibility{IncomeLimit: 250000},
+			models.StudentProfile{Income: 250000.01}, CriterionIncome, false, "income 250000.01 exceeds the limit of 250000.00"},
+		{"no income", models.Eligibility{IncomeLimit: 250000},
+			models.StudentProfile{}, CriterionIncome, true, ""},
+
+		{"qualification equal", models.Eligibility{AcademicQualification: models.AcademicQualificationClassXII},
+			models.StudentProfile{Qualification: "Class-XII"}, CriterionQualification, true, ""},
+		{"qualification higher", models.Eligibility{AcademicQualification: models.AcademicQualificationClassXII},
+			models.StudentProfile{Qualification: "Graduate"}, CriterionQualification, true, ""},
+		{"qualification lower", models.Eligibility{AcademicQualification: models.AcademicQualificationClassXII},
+			models.StudentProfile{Qualification: "Class-X"}, CriterionQualification, false, "qualification Class-X is below the required Class-XII"},
+		{"qualification unknown", models.Eligibility{AcademicQualification: models.AcademicQualificationClassXII},
+			models.StudentProfile{Qualification: "PhD"}, CriterionQualification, false, `unknown qualification "PhD"`},
+		{"qualification missing", models.Eligibility{AcademicQualification: models.AcademicQualificationClassXII},
+			models.StudentProfile{}, CriterionQualification, false, "qualification is missing"},
+
+		{"category matches", models.Eligibility{Category: models.CategoryOBC},
+			models.StudentProfile{Category: "OBC"}, CriterionCategory, true, ""},
+		{"category differs", models.Eligibility{Category: models.CategoryOBC},
+			models.StudentProfile{Category: "General"}, CriterionCategory, false, "category General does not match the required OBC"},
+		{"category missing", models.Eligibility{Category: models.CategoryOBC},
+			models.StudentProfile{}, CriterionCategory, false, "category is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := models.Scheme{Eligibility: tt.criteria}
+			report := EvaluateEligibility(&tt.profile, &scheme, evaluationTime)
+			if len(report.Criteria) != 1 {
+				t.Fatalf("evaluated %d criteria, want 1: %+v", len(report.Criteria), report.Criteria)
+			}
+			r := report.Criteria[0]
+			if r.Criterion != tt.criterion || r.Passed != tt.passed || r.Reason != tt.reason {
+				t.Errorf("got %s passed=%v reason=%q, want %s passed=%v reason=%q",
+					r.Criterion, r.Passed, r.Reason, tt.criterion, tt.passed, tt.reason)
+			}
+			if report.Eligible != tt.passed {
+				t.Errorf("Eligible = %v, want %v", report.Eligible, tt.passed)
+			}
+		})
+	}
+}
+
+func TestEvaluateEligibilitySkipsEmptyCriteria(t *testing.T) {
+	scheme := models.Scheme{Eligibility: models.Eligibility{AcademicQualification: models.AcademicQualificationNone}}
+	report := EvaluateEligibility(&models.StudentProfile{}, &scheme, evaluationTime)
+	if !report.Eligible || len(report.Criteria) != 0 {
+		t.Errorf("report = %+v, want eligible with no criteria", report)
+	}
+}
+
+func TestEvaluateEligibilityAllCriteria(t *testing.T) {
+	scheme := models.Scheme{Eligibility: models.Eligibility{
+		Gender:                models.GenderMale,
+		AgeMin:                18,
+		AgeMax:                30,
+		IncomeLimit:           300000,
+		AcademicQualification: models.AcademicQualificationGraduate,
+		Category:              models.CategorySC,
+	}}
+	profile := models.StudentProfile{
+		Gender:        "Male",
+		DateOfBirth:   birthDate(2000, time.January, 1),
+		Income:        300000,
+		Qualification: "Post-Graduate",
+		Category:      "SC",
+	}
+
+	report := EvaluateEligibility(&profile, &scheme, evaluationTime)
+	if !report.Eligible || len(report.Criteria) != 6 {
+		t.Fatalf("report = %+v, want eligible on 6 criteria", report)
+	}
+
+	profile.Category = "ST"
+	report = EvaluateEligibility(&profile, &scheme, evaluationTime)
+	if report.Eligible {
+		t.Fatal("a single failed criterion did not make the profile ineligible")
+	}
+}
+
+func TestRecommendSchemes(t *testing.T) {
+	profile := models.StudentProfile{Gender: "Female", Income: 100000, Category: "General"}
+	schemes := []models.Scheme{
+		{ID: 1, Amount: 1000, EndDate: date(2025, time.December, 1)},
+		{ID: 2, Amount: 5000, EndDate: date(2025, time.December, 1)},
+		{ID: 3, Amount: 5000, EndDate: date(2025, time.September, 1)},
+		{ID: 4, Eligibility: models.Eligibility{Gender: models.GenderMale}},
+		{ID: 5, Eligibility: models.Eligibility{Gender: models.GenderMale, Category: models.CategorySC}},
+	}
+
+	recs := RecommendSchemes(&profile, schemes, evaluationTime)
+
+	var eligible []uint
+	for _, s := range recs.Eligible {
+		eligible = append(eligible, s.ID)
+	}
+	if want := []uint{3, 2, 1}; len(eligible) != len(want) || eligible[0] != want[0] || eligible[1] != want[1] || eligible[2] != want[2] {
+		t.Errorf("eligible = %v, want %v", eligible, want)
+	}
+	if len(recs.NearMisses) != 1 || recs.NearMisses[0].Scheme.ID != 4 {
+		t.Fatalf("near misses = %+v, want only scheme 4", recs.NearMisses)
+	}
+	if reasons := recs.NearMisses[0].Reasons; len(reasons) != 1 || reasons[0] != "gender Female does not match the required Male" {
+		t.Errorf("near miss reasons = %q", reasons)
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// PasswordHashCost is the bcrypt work factor used for new hashes. Stored hashes
+	// with a lower cost are upgraded on the next successful login.
+	PasswordHashCost = 12
+
+	minPasswordLength = 8
+	// bcrypt ignores everything after the 72nd byte
+	maxPasswordLength = 72
+)
+
+// ErrWeakPassword is returned when a new password does not meet the password policy.
+var ErrWeakPassword = errors.New("password does not meet policy")
+
+// ValidatePasswordPolicy checks a new password against the minimum password policy.
+func ValidatePasswordPolicy(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: must be at most %d bytes", ErrWeakPassword, maxPasswordLength)
+	}
+	return nil
+}
+
+// HashPassword hashes a password with bcrypt.
+//
+// Parameters:
+// - password (string): The plain-text password.
+//
+// Returns:
+// - string: The encoded bcrypt hash.
+// - error: An error if hashing fails.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordHashCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword compares a plain-text password against a stored password.
+// Stored values that are not bcrypt hashes are treated as legacy plain text.
+//
+// Parameters:
+// - stored (string): The value from the users table.
+// - password (string): The plain-text password supplied by the user.
+//
+// Returns:
+// - bool: Whether the password matches.
+// - bool: Whether the stored value should be replaced by a fresh hash.
+func VerifyPassword(stored, password string) (match bool, needsRehash bool) {
+	if !isBcryptHash(stored) {
+		match = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return match, match
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || cost < PasswordHashCost
+}
+
+func isBcryptHash(s string) bool {
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
+
+// GenerateToken returns a random URL-safe token and the SHA-256 hash to store in its place.
+func GenerateToken() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the SHA-256 hash of a token in hex encoding.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
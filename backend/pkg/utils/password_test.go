@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isBcryptHash(hash) {
+		t.Fatalf("HashPassword returned %q, want a bcrypt hash", hash)
+	}
+	if cost, _ := bcrypt.Cost([]byte(hash)); cost != PasswordHashCost {
+		t.Errorf("cost = %d, want %d", cost, PasswordHashCost)
+	}
+
+	other, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == other {
+		t.Error("two hashes of the same password are equal; the salt is missing")
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cheap, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		stored      string
+		password    string
+		match       bool
+		needsRehash bool
+	}{
+		{"current hash", hash, "correct horse", true, false},
+		{"wrong password", hash, "battery staple", false, false},
+		{"low cost hash", string(cheap), "correct horse", true, true},
+		{"low cost hash, wrong password", string(cheap), "battery staple", false, false},
+		{"legacy plain text", "correct horse", "correct horse", true, true},
+		{"legacy plain text, wrong password", "correct horse", "battery staple", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash := VerifyPassword(tt.stored, tt.password)
+			if match != tt.match || needsRehash != tt.needsRehash {
+				t.Errorf("VerifyPassword = (%v, %v), want (%v, %v)", match, needsRehash, tt.match, tt.needsRehash)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		password string
+		ok       bool
+	}{
+		{"", false},
+		{"short", false},
+		{"12345678", true},
+		{strings.Repeat("a", maxPasswordLength), true},
+		{strings.Repeat("a", maxPasswordLength+1), false},
+	}
+	for _, tt := range tests {
+		err := ValidatePasswordPolicy(tt.password)
+		if tt.ok && err != nil {
+			t.Errorf("ValidatePasswordPolicy(%d bytes) = %v, want nil", len(tt.password), err)
+		}
+		if !tt.ok && !errors.Is(err, ErrWeakPassword) {
+			t.Errorf("ValidatePasswordPolicy(%d bytes) = %v, want ErrWeakPassword", len(tt.password), err)
+		}
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	token, hash, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 64 || len(hash) != 64 {
+		t.Errorf("token and hash lengths = %d, %d, want 64, 64", len(token), len(hash))
+	}
+	if HashToken(token) != hash {
+		t.Error("HashToken does not reproduce the stored hash")
+	}
+
+	next, _, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == token {
+		t.Error("GenerateToken returned the same token twice")
+	}
+}
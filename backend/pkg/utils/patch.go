@@ -0,0 +1,335 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+// PatchFormat is the media type of a patch document.
+type PatchFormat string
+
+const (
+	// PatchFormatMerge is a JSON Merge Patch (RFC 7396): the patch mirrors the document, null removes a member.
+	PatchFormatMerge PatchFormat = "application/merge-patch+json"
+	// PatchFormatJSON is a JSON Patch (RFC 6902): a list of add, remove, replace, move, copy and test operations.
+	PatchFormatJSON PatchFormat = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalidPatch is returned for malformed patch documents and operations on paths that do not exist.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPatchTestFailed is returned when a JSON Patch test operation does not match the document.
+	ErrPatchTestFailed = errors.New("patch test failed")
+)
+
+// ApplyPatch applies a patch to a JSON document.
+//
+// Parameters:
+// - doc ([]byte): The JSON document.
+// - patch ([]byte): The patch.
+// - format (PatchFormat): The kind of patch.
+//
+// Returns:
+// - []byte: The patched document.
+// - error: An error wrapping ErrInvalidPatch or ErrPatchTestFailed.
+func ApplyPatch(doc, patch []byte, format PatchFormat) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	var err error
+	switch format {
+	case PatchFormatMerge:
+		var p interface{}
+		if err := json.Unmarshal(patch, &p); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		target = mergePatch(target, p)
+	case PatchFormatJSON:
+		var ops []models.JSONPatchOperation
+		if err := json.Unmarshal(patch, &ops); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		for i, op := range ops {
+			if target, err = applyPatchOperation(target, op); err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %s", ErrInvalidPatch, format)
+	}
+	return json.Marshal(target)
+}
+
+// mergePatch applies an RFC 7396 merge patch.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = mergePatch(t[key], value)
+		}
+	}
+	return t
+}
+
+// applyPatchOperation applies one RFC 6902 operation and returns the new document.
+func applyPatchOperation(doc interface{}, op models.JSONPatchOperation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: %s needs a value", ErrInvalidPatch, op.Op)
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if op.From == op.Path {
+				return doc, nil
+			}
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalidPatch, op.From)
+			}
+			if doc, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopyJSON(value)
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+
+	switch op.Op {
+	case "remove":
+		return pointerRemove(doc, path)
+	case "replace":
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, err = pointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case "test":
+		actual, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, fmt.Errorf("%w: %s does not have the expected value", ErrPatchTestFailed, op.Path)
+		}
+		return doc, nil
+	default:
+		return pointerAdd(doc, path, value)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array index token; max is the largest index allowed.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	return i, nil
+}
+
+func pointerGet(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+			}
+			node = child
+		case []interface{}:
+			i, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+		}
+	}
+	return node, nil
+}
+
+// pointerAdd sets the value at path and returns the new node. A value added to an array is
+// inserted before the index, or appended for "-".
+func pointerAdd(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+		}
+		child, err := pointerAdd(child, rest, value)
+		n[token] = child
+		return n, err
+	case []interface{}:
+		if len(rest) == 0 {
+			if token == "-" {
+				return append(n, value), nil
+			}
+			i, err := arrayIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			return append(n[:i], append([]interface{}{value}, n[i:]...)...), nil
+		}
+		i, err := arrayIndex(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := pointerAdd(n[i], rest, value)
+		n[i] = child
+		return n, err
+	default:
+		return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+	}
+}
+
+// pointerRemove deletes the value at path and returns the new node.
+func pointerRemove(node interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+		}
+		if len(rest) == 0 {
+			delete(n, token)
+			return n, nil
+		}
+		child, err := pointerRemove(child, rest)
+		n[token] = child
+		return n, err
+	case []interface{}:
+		i, err := arrayIndex(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			return append(n[:i:i], n[i+1:]...), nil
+		}
+		child, err := pointerRemove(n[i], rest)
+		n[i] = child
+		return n, err
+	default:
+		return nil, fmt.Errorf("%w: %s does not exist", ErrInvalidPatch, token)
+	}
+}
+
+func deepCopyJSON(value interface{}) interface{} {
+	raw, _ := json.Marshal(value)
+	var c interface{}
+	_ = json.Unmarshal(raw, &c)
+	return c
+}
+
+// DecodePatchedInput decodes a patched document into an input struct, rejecting unknown fields.
+//
+// Parameters:
+// - doc ([]byte): The patched document.
+// - input (interface{}): A pointer to the input struct.
+//
+// Returns:
+// - error: ValidationErrors for values of the wrong type, an error wrapping ErrInvalidPatch for
+// unknown fields, or nil.
+func DecodePatchedInput(doc []byte, input interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(input)
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr):
+		return ValidationErrors{{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type)}}
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+}
+
+// jsonTypeName describes the JSON value a Go type is decoded from.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	default:
+		return "an object"
+	}
+}
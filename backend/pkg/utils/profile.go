@@ -0,0 +1,430 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// studentProfileFields are the columns of a student profile that hold applicant data.
+var studentProfileFields = []string{
+	"FullName", "DateOfBirth", "Gender", "PhoneNumber", "Email", "Qualification",
+	"AadhaarNumber", "Nationality", "Category", "Income", "IsInternational",
+}
+
+// PreloadStudentProfile preloads the documents, education history and addresses of a student profile.
+func PreloadStudentProfile(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Documents").
+		Preload("EducationHistory").
+		Preload("Addresses")
+}
+
+// FindApplicantProfile loads the saved profile of an applicant. Draft applications share this
+// profile, so whatever the applicant enters once is reused by every new application.
+// Applicants who only have per-application profiles from before saved profiles existed get
+// their most recently updated one. Nothing is written, so use EnsureApplicantProfile before
+// changing the profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - userID (uint): The ID of the applicant.
+//
+// Returns:
+// - *models.StudentProfile: The saved profile with documents, education history and addresses.
+// - error: gorm.ErrRecordNotFound if the applicant has no profile at all, or a database error.
+func FindApplicantProfile(tx *gorm.DB, userID uint) (*models.StudentProfile, error) {
+	var profile models.StudentProfile
+	err := PreloadStudentProfile(tx).Where("user_id = ? AND is_primary", userID).First(&profile).Error
+	if err == nil {
+		return &profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := PreloadStudentProfile(tx).Where("user_id = ?", userID).Order("updated_at DESC").First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// EnsureApplicantProfile loads the saved profile of an applicant, creating it if needed from
+// the applicant's most recent per-application profile or as an empty profile.
+// A concurrent request creating the same profile is resolved by loading the one it stored.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - userID (uint): The ID of the applicant.
+//
+// Returns:
+// - *models.StudentProfile: The saved profile with documents, education history and addresses.
+// - error: An error if the profile cannot be loaded or created.
+func EnsureApplicantProfile(tx *gorm.DB, userID uint) (*models.StudentProfile, error) {
+	profile, err := FindApplicantProfile(tx, userID)
+	switch {
+	case err == nil && profile.IsPrimary:
+		return profile, nil
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	var created *models.StudentProfile
+	// The savepoint keeps the surrounding transaction usable if the insert loses the race
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		if profile != nil {
+			clone, err := CloneStudentProfile(tx, profile, true)
+			created = clone
+			return err
+		}
+		created = &models.StudentProfile{UserID: userID, IsPrimary: true}
+		if err := tx.Omit("Documents", "EducationHistory", "Addresses").Create(created).Error; err != nil {
+			return fmt.Errorf("failed to create student profile: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		var saved models.StudentProfile
+		if err := PreloadStudentProfile(tx).Where("user_id = ? AND is_primary", userID).First(&saved).Error; err != nil {
+			return nil, fmt.Errorf("failed to load student profile: %w", err)
+		}
+		return &saved, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CloneStudentProfile copies a student profile together with its documents, education history
+// and addresses. Copied documents point at the same files. They keep their verification state
+// in per-application copies, while a new saved profile starts with every document pending,
+// since a review of one application does not verify documents for the next.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - src (*models.StudentProfile): The profile to copy, loaded with PreloadStudentProfile.
+// - primary (bool): Whether the copy becomes the applicant's saved profile.
+//
+// Returns:
+// - *models.StudentProfile: The new profile.
+// - error: An error if the copy cannot be stored.
+func CloneStudentProfile(tx *gorm.DB, src *models.StudentProfile, primary bool) (*models.StudentProfile, error) {
+	clone := *src
+	clone.ID = 0
+	clone.IsPrimary = primary
+	clone.CreatedAt = time.Time{}
+	clone.UpdatedAt = time.Time{}
+	clone.Documents = nil
+	clone.EducationHistory = nil
+	clone.Addresses = nil
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Documents", "EducationHistory", "Addresses").Create(&clone).Error; err != nil {
+			return fmt.Errorf("failed to copy student profile: %w", err)
+		}
+		for _, doc := range src.Documents {
+			doc.ID = 0
+			doc.StudentID = clone.ID
+			if primary {
+				doc.VerificationStatus = models.DocumentPending
+				doc.RejectionReason = ""
+				doc.VerifiedByID = nil
+				doc.VerifiedAt = nil
+			}
+			if err := tx.Omit("StudentProfile").Create(&doc).Error; err != nil {
+				return fmt.Errorf("failed to copy document: %w", err)
+			}
+			clone.Documents = append(clone.Documents, doc)
+		}
+		for _, edu := range src.EducationHistory {
+			edu.ID = 0
+			edu.StudentID = clone.ID
+			if err := tx.Create(&edu).Error; err != nil {
+				return fmt.Errorf("failed to copy education history: %w", err)
+			}
+			clone.EducationHistory = append(clone.EducationHistory, edu)
+		}
+		for _, addr := range src.Addresses {
+			addr.ID = 0
+			addr.StudentID = clone.ID
+			if err := tx.Create(&addr).Error; err != nil {
+				return fmt.Errorf("failed to copy address: %w", err)
+			}
+			clone.Addresses = append(clone.Addresses, addr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// SnapshotApplicationProfile gives an application that still uses the applicant's saved profile,
+// or a profile it shares with another application, a copy of its own, so later edits elsewhere
+// do not change what was submitted. Applications that already own their profile are left as they are.
+//
+// Parameters:
+// - tx (*gorm.DB): The database transaction.
+// - app (*models.Application): The application being submitted.
+//
+// Returns:
+// - error: An error if the copy cannot be stored.
+func SnapshotApplicationProfile(tx *gorm.DB, app *models.Application) error {
+	var profile models.StudentProfile
+	if err := PreloadStudentProfile(tx).First(&profile, app.StudentProfileID).Error; err != nil {
+		return fmt.Errorf("failed to load student profile: %w", err)
+	}
+	if !profile.IsPrimary {
+		var shared int64
+		if err := tx.Model(&models.Application{}).
+			Where("student_profile_id = ? AND id <> ?", profile.ID, app.ID).
+			Count(&shared).Error; err != nil {
+			return fmt.Errorf("failed to check student profile use: %w", err)
+		}
+		if shared == 0 {
+			return nil
+		}
+	}
+
+	snapshot, err := CloneStudentProfile(tx, &profile, false)
+	if err != nil {
+		return err
+	}
+	app.StudentProfileID = snapshot.ID
+	app.StudentProfile = *snapshot
+	return nil
+}
+
+// OverwriteStudentProfileFields replaces the applicant data of a stored profile, including empty
+// values, with the fields of another profile. Documents, addresses and education history are kept.
+func OverwriteStudentProfileFields(tx *gorm.DB, profileID uint, data *models.StudentProfile) error {
+	if err := tx.Model(&models.StudentProfile{ID: profileID}).Select(studentProfileFields).Updates(data).Error; err != nil {
+		return fmt.Errorf("failed to update student profile: %w", err)
+	}
+	return nil
+}
+
+// ApplyStudentProfileInput copies the non-empty fields of a profile input onto a student profile.
+// Documents, addresses and education history are stored separately by the Upsert helpers.
+func ApplyStudentProfileInput(profile *models.StudentProfile, input models.StudentProfileInput) {
+	if input.FullName != "" {
+		profile.FullName = input.FullName
+	}
+	if input.Email != "" {
+		profile.Email = input.Email
+	}
+	if input.PhoneNumber != "" {
+		profile.PhoneNumber = input.PhoneNumber
+	}
+	if input.DateOfBirth != nil {
+		profile.DateOfBirth = input.DateOfBirth
+	}
+	if input.Qualification != "" {
+		profile.Qualification = input.Qualification
+	}
+	if input.Category != "" {
+		profile.Category = input.Category
+	}
+	if input.Income != nil {
+		profile.Income = *input.Income
+	}
+	if input.Nationality != "" {
+		profile.Nationality = input.Nationality
+	}
+	if input.Gender != "" {
+		profile.Gender = input.Gender
+	}
+	if input.AadhaarNumber != "" {
+		profile.AadhaarNumber = input.AadhaarNumber
+	}
+}
+
+// UpdateStudentProfile applies a profile input to a student profile and stores the profile,
+// its documents, addresses and education history in one transaction.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - profile (*models.StudentProfile): The profile to update.
+// - input (models.StudentProfileInput): The submitted profile data.
+//
+// Returns:
+// - error: ValidationErrors if the input is invalid, or an error if any part of the update fails;
+// nothing is stored in either case.
+func UpdateStudentProfile(tx *gorm.DB, profile *models.StudentProfile, input models.StudentProfileInput) error {
+	if err := ValidateStudentProfileInput(input); err != nil {
+		return err
+	}
+	ApplyStudentProfileInput(profile, input)
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Documents", "Addresses", "EducationHistory").Save(profile).Error; err != nil {
+			return fmt.Errorf("failed to update student profile: %w", err)
+		}
+		if err := UpsertStudentDocuments(tx, profile.ID, input.Documents); err != nil {
+			return err
+		}
+		if err := UpsertStudentAddresses(tx, profile.ID, input.Addresses); err != nil {
+			return fmt.Errorf("failed to upsert address: %w", err)
+		}
+		return UpsertEducationHistory(tx, profile.ID, input.EducationHistory)
+	})
+}
+
+// StudentProfileToInput converts a stored profile into the input shape used to edit it. Only a
+// missing date of birth becomes nil; an income of zero is kept.
+//
+// Parameters:
+// - profile (*models.StudentProfile): The profile, loaded with PreloadStudentProfile.
+//
+// Returns:
+// - models.StudentProfileInput: The profile as input.
+func StudentProfileToInput(profile *models.StudentProfile) models.StudentProfileInput {
+	input := models.StudentProfileInput{
+		FullName:         profile.FullName,
+		Gender:           profile.Gender,
+		PhoneNumber:      profile.PhoneNumber,
+		Qualification:    profile.Qualification,
+		Email:            profile.Email,
+		AadhaarNumber:    profile.AadhaarNumber,
+		Nationality:      profile.Nationality,
+		Category:         profile.Category,
+		IsInternational:  profile.IsInternational,
+		Documents:        make([]models.DocumentInput, 0, len(profile.Documents)),
+		Addresses:        make([]models.AddressInput, 0, len(profile.Addresses)),
+		EducationHistory: make([]models.EducationHistoryInput, 0, len(profile.EducationHistory)),
+	}
+	income := profile.Income
+	input.Income = &income
+	if profile.DateOfBirth != nil {
+		dob := *profile.DateOfBirth
+		input.DateOfBirth = &dob
+	}
+	for _, doc := range profile.Documents {
+		input.Documents = append(input.Documents, models.DocumentInput{ID: doc.ID, Name: doc.Name, URL: doc.URL})
+	}
+	for _, addr := range profile.Addresses {
+		input.Addresses = append(input.Addresses, models.AddressInput{
+			ID:      addr.ID,
+			Type:    addr.Type,
+			Street:  addr.Street,
+			City:    addr.City,
+			State:   addr.State,
+			Pincode: addr.Pincode,
+			Country: addr.Country,
+		})
+	}
+	for _, edu := range profile.EducationHistory {
+		input.EducationHistory = append(input.EducationHistory, models.EducationHistoryInput{
+			ID:            edu.ID,
+			Degree:        edu.Degree,
+			University:    edu.University,
+			YearOfPassing: edu.YearOfPassing,
+			Grade:         edu.Grade,
+			Course:        edu.Course,
+		})
+	}
+	return input
+}
+
+// ReplaceStudentProfile makes a stored profile match an input exactly: empty fields are cleared,
+// and documents, addresses and education entries missing from the input are removed. Entries are
+// matched by ID; documents without an ID are matched by name and addresses without one by type.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - profile (*models.StudentProfile): The profile, loaded with PreloadStudentProfile.
+// - input (models.StudentProfileInput): The complete new profile.
+//
+// Returns:
+// - []models.UploadDocument: The removed documents, whose stored files the caller may discard.
+// - error: ValidationErrors if the input is invalid, or an error if any part of the update fails;
+// nothing is stored in either case.
+func ReplaceStudentProfile(tx *gorm.DB, profile *models.StudentProfile, input models.StudentProfileInput) ([]models.UploadDocument, error) {
+	if err := ValidateStudentProfileInput(input); err != nil {
+		return nil, err
+	}
+
+	profile.FullName = input.FullName
+	profile.Gender = input.Gender
+	profile.PhoneNumber = input.PhoneNumber
+	profile.Qualification = input.Qualification
+	profile.Email = input.Email
+	profile.AadhaarNumber = input.AadhaarNumber
+	profile.Nationality = input.Nationality
+	profile.Category = input.Category
+	profile.IsInternational = input.IsInternational
+	profile.DateOfBirth = input.DateOfBirth
+	profile.Income = 0
+	if input.Income != nil {
+		profile.Income = *input.Income
+	}
+
+	keepDocuments := make(map[string]bool, len(input.Documents))
+	keepDocumentIDs := make(map[uint]bool, len(input.Documents))
+	for _, doc := range input.Documents {
+		if doc.ID != 0 {
+			keepDocumentIDs[doc.ID] = true
+		} else {
+			keepDocuments[doc.Name] = doc.URL != ""
+		}
+	}
+	keepAddresses := make(map[string]bool, len(input.Addresses))
+	keepAddressIDs := make(map[uint]bool, len(input.Addresses))
+	for _, addr := range input.Addresses {
+		if addr.ID != 0 {
+			keepAddressIDs[addr.ID] = true
+		} else {
+			keepAddresses[addr.Type] = addr.Street != "" || addr.City != "" || addr.State != "" || addr.Pincode != "" || addr.Country != ""
+		}
+	}
+	keepEducation := make(map[uint]bool, len(input.EducationHistory))
+	for _, edu := range input.EducationHistory {
+		keepEducation[edu.ID] = true
+	}
+
+	var removed []models.UploadDocument
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Documents", "Addresses", "EducationHistory").Save(profile).Error; err != nil {
+			return fmt.Errorf("failed to update student profile: %w", err)
+		}
+
+		for _, doc := range profile.Documents {
+			if keepDocumentIDs[doc.ID] || keepDocuments[doc.Name] {
+				continue
+			}
+			if _, err := DeleteStudentDocument(tx, profile.ID, doc.ID); err != nil {
+				return err
+			}
+			removed = append(removed, doc)
+		}
+		for _, addr := range profile.Addresses {
+			if !keepAddressIDs[addr.ID] && !keepAddresses[addr.Type] {
+				if err := DeleteStudentAddress(tx, profile.ID, addr.ID); err != nil {
+					return fmt.Errorf("failed to delete address: %w", err)
+				}
+			}
+		}
+		for _, edu := range profile.EducationHistory {
+			if !keepEducation[edu.ID] {
+				if err := DeleteEducationEntry(tx, profile.ID, edu.ID); err != nil {
+					return fmt.Errorf("failed to delete education record: %w", err)
+				}
+			}
+		}
+
+		if err := UpsertStudentDocuments(tx, profile.ID, input.Documents); err != nil {
+			return err
+		}
+		if err := UpsertStudentAddresses(tx, profile.ID, input.Addresses); err != nil {
+			return fmt.Errorf("failed to upsert address: %w", err)
+		}
+		return UpsertEducationHistory(tx, profile.ID, input.EducationHistory)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
@@ -0,0 +1,332 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDuplicateEntry is returned when a profile already has a document with the same name or an
+// address of the same type.
+var ErrDuplicateEntry = errors.New("duplicate entry")
+
+// FindEditableApplication loads and locks an applicant's application for a change.
+// Call it inside a transaction so the application cannot be submitted until the change is stored.
+//
+// Parameters:
+// - tx (*gorm.DB): The database transaction.
+// - userID (uint): The ID of the applicant.
+// - applicationID (string): The ID of the application.
+//
+// Returns:
+// - *models.Application: The application.
+// - error: gorm.ErrRecordNotFound if the applicant has no such application, an error wrapping
+// ErrApplicationNotEditable, or a database error.
+func FindEditableApplication(tx *gorm.DB, userID uint, applicationID string) (*models.Application, error) {
+	var application models.Application
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND id = ?", userID, applicationID).
+		First(&application).Error; err != nil {
+		return nil, err
+	}
+	if !application.IsDraft {
+		return nil, fmt.Errorf("%w: application is %s", ErrApplicationNotEditable, application.Status)
+	}
+	return &application, nil
+}
+
+// requireFields checks the validate tags of an entry input and reports the fields, given as
+// name and value pairs, that are empty.
+func requireFields(input interface{}, fields ...[2]string) error {
+	var errs ValidationErrors
+	if err := ValidateStruct(input); err != nil {
+		if !errors.As(err, &errs) {
+			return err
+		}
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			errs.Add(field[0], "is required")
+		}
+	}
+	return errs.Err()
+}
+
+// CreateStudentDocument attaches a document to a student profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - input (models.DocumentInput): The document name and URL.
+//
+// Returns:
+// - *models.UploadDocument: The new document, pending verification.
+// - error: ValidationErrors, an error wrapping ErrDuplicateEntry if the profile already has a
+// document with that name, or a database error.
+func CreateStudentDocument(tx *gorm.DB, studentID uint, input models.DocumentInput) (*models.UploadDocument, error) {
+	if err := requireFields(input, [2]string{"name", input.Name}, [2]string{"url", input.URL}); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := tx.Model(&models.UploadDocument{}).
+		Where("student_id = ? AND name = ?", studentID, input.Name).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("error checking document: %w", err)
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w: document %s is already attached", ErrDuplicateEntry, input.Name)
+	}
+
+	document := models.UploadDocument{
+		StudentID:          studentID,
+		Name:               input.Name,
+		URL:                input.URL,
+		VerificationStatus: models.DocumentPending,
+	}
+	if err := tx.Omit("StudentProfile").Create(&document).Error; err != nil {
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+	return &document, nil
+}
+
+// UpdateStudentDocument renames a document of a student profile or points it at another URL.
+// A new URL replaces the stored file, so the document has to be verified again; a signed link to
+// the stored file counts as its current URL.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - id (uint): The ID of the document.
+// - input (models.DocumentInput): The new name and, optionally, URL; its ID is ignored.
+//
+// Returns:
+// - *models.UploadDocument: The updated document.
+// - string: The storage key of a replaced file, which the caller discards once the transaction
+// is committed, or "".
+// - error: gorm.ErrRecordNotFound if the profile has no such document, ValidationErrors, an error
+// wrapping ErrDuplicateEntry if the name is taken by another document, or a database error.
+func UpdateStudentDocument(tx *gorm.DB, studentID, id uint, input models.DocumentInput) (*models.UploadDocument, string, error) {
+	var document models.UploadDocument
+	if err := tx.Where("id = ? AND student_id = ?", id, studentID).First(&document).Error; err != nil {
+		return nil, "", err
+	}
+	if err := requireFields(input, [2]string{"name", input.Name}); err != nil {
+		return nil, "", err
+	}
+
+	var count int64
+	if err := tx.Model(&models.UploadDocument{}).
+		Where("student_id = ? AND name = ? AND id <> ?", studentID, input.Name, id).
+		Count(&count).Error; err != nil {
+		return nil, "", fmt.Errorf("error checking document: %w", err)
+	}
+	if count > 0 {
+		return nil, "", fmt.Errorf("%w: document %s is already attached", ErrDuplicateEntry, input.Name)
+	}
+
+	var replacedKey string
+	document.Name = input.Name
+	if input.URL != "" && input.URL != document.URL && !storage.IsLinkTo(input.URL, document.StorageKey) {
+		replacedKey = document.StorageKey
+		resetDocumentFile(&document, input.URL)
+	}
+	if err := tx.Omit("StudentProfile").Save(&document).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to update document: %w", err)
+	}
+	return &document, replacedKey, nil
+}
+
+// resetDocumentFile points a document at a new URL. A replaced file has to be verified again.
+func resetDocumentFile(document *models.UploadDocument, url string) {
+	document.URL = url
+	document.StorageKey = ""
+	document.ContentType = ""
+	document.Size = 0
+	document.Checksum = ""
+	document.VerificationStatus = models.DocumentPending
+	document.RejectionReason = ""
+	document.VerifiedByID = nil
+	document.VerifiedAt = nil
+	document.UpdatedAt = time.Now()
+}
+
+// DeleteStudentDocument removes a document from a student profile. The stored file is left
+// alone; the caller discards it once the transaction is committed.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - id (uint): The ID of the document.
+//
+// Returns:
+// - *models.UploadDocument: The removed document.
+// - error: gorm.ErrRecordNotFound if the profile has no such document, or a database error.
+func DeleteStudentDocument(tx *gorm.DB, studentID, id uint) (*models.UploadDocument, error) {
+	var document models.UploadDocument
+	if err := tx.Where("id = ? AND student_id = ?", id, studentID).First(&document).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Delete(&document).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete document: %w", err)
+	}
+	return &document, nil
+}
+
+// CreateStudentAddress adds an address to a student profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - input (models.AddressInput): The address.
+//
+// Returns:
+// - *models.Address: The new address.
+// - error: ValidationErrors, an error wrapping ErrDuplicateEntry if the profile already has an
+// address of that type, or a database error.
+func CreateStudentAddress(tx *gorm.DB, studentID uint, input models.AddressInput) (*models.Address, error) {
+	address := models.Address{StudentID: studentID}
+	if err := saveStudentAddress(tx, &address, input); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// UpdateStudentAddress replaces an address of a student profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - id (uint): The ID of the address.
+// - input (models.AddressInput): The new address.
+//
+// Returns:
+// - *models.Address: The updated address.
+// - error: gorm.ErrRecordNotFound if the profile has no such address, ValidationErrors, an error
+// wrapping ErrDuplicateEntry if the type is taken by another address, or a database error.
+func UpdateStudentAddress(tx *gorm.DB, studentID, id uint, input models.AddressInput) (*models.Address, error) {
+	var address models.Address
+	if err := tx.Where("id = ? AND student_id = ?", id, studentID).First(&address).Error; err != nil {
+		return nil, err
+	}
+	if err := saveStudentAddress(tx, &address, input); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func saveStudentAddress(tx *gorm.DB, address *models.Address, input models.AddressInput) error {
+	if err := requireFields(input, [2]string{"type", input.Type}); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.Address{}).
+		Where("student_id = ? AND type = ? AND id <> ?", address.StudentID, input.Type, address.ID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("error checking address: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: the profile already has a %s address", ErrDuplicateEntry, input.Type)
+	}
+
+	address.Type = input.Type
+	address.Street = input.Street
+	address.City = input.City
+	address.State = input.State
+	address.Pincode = input.Pincode
+	address.Country = input.Country
+	if err := tx.Save(address).Error; err != nil {
+		return fmt.Errorf("failed to save address: %w", err)
+	}
+	return nil
+}
+
+// DeleteStudentAddress removes an address from a student profile.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the profile has no such address, or a database error.
+func DeleteStudentAddress(tx *gorm.DB, studentID, id uint) error {
+	return deleteProfileEntry(tx, &models.Address{}, studentID, id)
+}
+
+// CreateEducationEntry adds an entry to the education history of a student profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - input (models.EducationHistoryInput): The entry; its ID is ignored.
+//
+// Returns:
+// - *models.StudentAcademicQualification: The new entry.
+// - error: ValidationErrors or a database error.
+func CreateEducationEntry(tx *gorm.DB, studentID uint, input models.EducationHistoryInput) (*models.StudentAcademicQualification, error) {
+	entry := models.StudentAcademicQualification{StudentID: studentID}
+	if err := saveEducationEntry(tx, &entry, input); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEducationEntry replaces an entry of the education history of a student profile.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - studentID (uint): The ID of the student profile.
+// - id (uint): The ID of the entry.
+// - input (models.EducationHistoryInput): The new entry; its ID is ignored.
+//
+// Returns:
+// - *models.StudentAcademicQualification: The updated entry.
+// - error: gorm.ErrRecordNotFound if the profile has no such entry, ValidationErrors, or a database error.
+func UpdateEducationEntry(tx *gorm.DB, studentID, id uint, input models.EducationHistoryInput) (*models.StudentAcademicQualification, error) {
+	var entry models.StudentAcademicQualification
+	if err := tx.Where("id = ? AND student_id = ?", id, studentID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	if err := saveEducationEntry(tx, &entry, input); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func saveEducationEntry(tx *gorm.DB, entry *models.StudentAcademicQualification, input models.EducationHistoryInput) error {
+	if err := requireFields(input, [2]string{"degree", input.Degree}); err != nil {
+		return err
+	}
+
+	entry.Degree = input.Degree
+	entry.University = input.University
+	entry.YearOfPassing = input.YearOfPassing
+	entry.Grade = input.Grade
+	entry.Course = input.Course
+	if err := tx.Save(entry).Error; err != nil {
+		return fmt.Errorf("failed to save education record: %w", err)
+	}
+	return nil
+}
+
+// DeleteEducationEntry removes an entry from the education history of a student profile.
+//
+// Returns:
+// - error: gorm.ErrRecordNotFound if the profile has no such entry, or a database error.
+func DeleteEducationEntry(tx *gorm.DB, studentID, id uint) error {
+	return deleteProfileEntry(tx, &models.StudentAcademicQualification{}, studentID, id)
+}
+
+// deleteProfileEntry deletes the row of model with the given ID if it belongs to the student profile.
+func deleteProfileEntry(tx *gorm.DB, model interface{}, studentID, id uint) error {
+	result := tx.Where("id = ? AND student_id = ?", id, studentID).Delete(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestUpsertStudentDocumentsByID(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	_, profile := legacyProfile(t, conn)
+	document := profile.Documents[0]
+
+	renamed := []models.DocumentInput{{ID: document.ID, Name: "aadhaar_card", URL: document.URL}}
+	if err := UpsertStudentDocuments(conn, profile.ID, renamed); err != nil {
+		t.Fatal(err)
+	}
+	var documents []models.UploadDocument
+	if err := conn.Where("student_id = ?", profile.ID).Find(&documents).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(documents) != 1 {
+		t.Fatalf("%d documents after renaming, want 1", len(documents))
+	}
+	if got := documents[0]; got.ID != document.ID || got.Name != "aadhaar_card" {
+		t.Errorf("renamed document = %d %q, want %d %q", got.ID, got.Name, document.ID, "aadhaar_card")
+	}
+	if got := documents[0]; got.VerificationStatus != models.DocumentVerified || got.StorageKey != document.StorageKey {
+		t.Errorf("renaming reset the file: %+v", got)
+	}
+
+	err := UpsertStudentDocuments(conn, profile.ID, []models.DocumentInput{{ID: document.ID + 1000, Name: "pan_card", URL: "https://files.example.org/pan.pdf"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "documents[0].id" {
+		t.Errorf("unknown ID: err = %v, want a documents[0].id validation error", err)
+	}
+}
+
+func TestUpdateStudentDocumentReplacesFile(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	_, profile := legacyProfile(t, conn)
+	document := profile.Documents[0]
+
+	updated, replacedKey, err := UpdateStudentDocument(conn, profile.ID, document.ID, models.DocumentInput{Name: document.Name, URL: "https://files.example.org/aadhar.pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replacedKey != document.StorageKey {
+		t.Errorf("replaced key = %q, want %q", replacedKey, document.StorageKey)
+	}
+	if updated.StorageKey != "" || updated.VerificationStatus != models.DocumentPending || updated.VerifiedByID != nil {
+		t.Errorf("document with a new URL kept its file or verification: %+v", updated)
+	}
+
+	if _, _, err := UpdateStudentDocument(conn, profile.ID+1000, document.ID, models.DocumentInput{Name: "pan_card"}); err == nil {
+		t.Error("UpdateStudentDocument() changed a document of another profile")
+	}
+}
+
+func TestUpsertStudentAddressesByID(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	_, profile := legacyProfile(t, conn)
+	address, err := CreateStudentAddress(conn, profile.ID, models.AddressInput{Type: "permanent", City: "Pune"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved := []models.AddressInput{{ID: address.ID, Type: "current", City: "Mumbai"}}
+	if err := UpsertStudentAddresses(conn, profile.ID, moved); err != nil {
+		t.Fatal(err)
+	}
+	var addresses []models.Address
+	if err := conn.Where("student_id = ?", profile.ID).Find(&addresses).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(addresses) != 1 {
+		t.Fatalf("%d addresses after the update, want 1", len(addresses))
+	}
+	if got := addresses[0]; got.ID != address.ID || got.Type != "current" || got.City != "Mumbai" {
+		t.Errorf("updated address = %+v, want address %d in Mumbai of type current", got, address.ID)
+	}
+
+	err = UpsertStudentAddresses(conn, profile.ID, []models.AddressInput{{ID: address.ID + 1000, Type: "permanent", City: "Pune"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "addresses[0].id" {
+		t.Errorf("unknown ID: err = %v, want an addresses[0].id validation error", err)
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// legacyProfile stores an applicant with a per-application profile holding a verified document.
+func legacyProfile(t *testing.T, conn *gorm.DB) (*models.User, *models.StudentProfile) {
+	t.Helper()
+	user, err := CreateUser(conn, "asha", "asha@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer, err := CreateUser(conn, "ravi", "ravi@example.org", "correct horse", models.RoleReviewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiedAt := time.Now()
+	profile := models.StudentProfile{
+		UserID:   user.ID,
+		FullName: "Asha Rao",
+		Documents: []models.UploadDocument{{
+			Name:               "aadhar_card",
+			StorageKey:         "applications/1/aadhar_card.pdf",
+			VerificationStatus: models.DocumentVerified,
+			VerifiedByID:       &reviewer.ID,
+			VerifiedAt:         &verifiedAt,
+		}},
+	}
+	if err := conn.Create(&profile).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user, &profile
+}
+
+func countProfiles(t *testing.T, conn *gorm.DB, userID uint) int64 {
+	t.Helper()
+	var n int64
+	if err := conn.Model(&models.StudentProfile{}).Where("user_id = ?", userID).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestFindApplicantProfileDoesNotWrite(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	user, legacy := legacyProfile(t, conn)
+
+	found, err := FindApplicantProfile(conn, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != legacy.ID {
+		t.Errorf("FindApplicantProfile() = profile %d, want the legacy profile %d", found.ID, legacy.ID)
+	}
+	if n := countProfiles(t, conn, user.ID); n != 1 {
+		t.Errorf("%d profiles after FindApplicantProfile(), want 1", n)
+	}
+}
+
+func TestEnsureApplicantProfileResetsVerification(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	user, legacy := legacyProfile(t, conn)
+
+	saved, err := EnsureApplicantProfile(conn, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == legacy.ID || !saved.IsPrimary {
+		t.Fatalf("EnsureApplicantProfile() = profile %d (primary %v), want a new saved profile", saved.ID, saved.IsPrimary)
+	}
+	if len(saved.Documents) != 1 {
+		t.Fatalf("saved profile has %d documents, want 1", len(saved.Documents))
+	}
+	if doc := saved.Documents[0]; doc.VerificationStatus != models.DocumentPending || doc.VerifiedByID != nil || doc.VerifiedAt != nil {
+		t.Errorf("copied document kept its verification: %+v", doc)
+	}
+
+	var original models.UploadDocument
+	if err := conn.Where("student_id = ?", legacy.ID).First(&original).Error; err != nil {
+		t.Fatal(err)
+	}
+	if original.VerificationStatus != models.DocumentVerified {
+		t.Errorf("legacy document is %s, want it left verified", original.VerificationStatus)
+	}
+
+	again, err := EnsureApplicantProfile(conn, user.ID)
+	if err != nil || again.ID != saved.ID {
+		t.Errorf("second EnsureApplicantProfile() = %v, %v, want profile %d", again, err, saved.ID)
+	}
+}
+
+func TestEnsureApplicantProfileConcurrent(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	user, err := CreateUser(conn, "asha", "asha@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const requests = 8
+	ids := make([]uint, requests)
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = conn.Transaction(func(tx *gorm.DB) error {
+				profile, err := EnsureApplicantProfile(tx, user.ID)
+				if err == nil {
+					ids[i] = profile.ID
+				}
+				return err
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range ids {
+		if errs[i] != nil {
+			t.Fatalf("request %d: %v", i, errs[i])
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("request %d got profile %d, request 0 got %d", i, ids[i], ids[0])
+		}
+	}
+	if n := countProfiles(t, conn, user.ID); n != 1 {
+		t.Errorf("%d profiles after concurrent requests, want 1", n)
+	}
+}
+
+func TestSnapshotApplicationProfileShared(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	user, legacy := legacyProfile(t, conn)
+	scheme := models.Scheme{Name: "Merit scholarship", Status: "open", Eligibility: models.Eligibility{Category: "General"}}
+	if err := conn.Create(&scheme).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	newApplication := func() *models.Application {
+		app := models.Application{UserID: user.ID, SchemeID: scheme.ID, StudentProfileID: legacy.ID, IsDraft: true}
+		if err := conn.Omit("User", "Scheme", "StudentProfile").Create(&app).Error; err != nil {
+			t.Fatal(err)
+		}
+		return &app
+	}
+	first, second := newApplication(), newApplication()
+
+	if err := SnapshotApplicationProfile(conn, first); err != nil {
+		t.Fatal(err)
+	}
+	if first.StudentProfileID == legacy.ID {
+		t.Error("application sharing its profile kept it")
+	}
+	if err := conn.Model(first).Update("student_profile_id", first.StudentProfileID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// The second application now owns the legacy profile on its own
+	if err := SnapshotApplicationProfile(conn, second); err != nil {
+		t.Fatal(err)
+	}
+	if second.StudentProfileID != legacy.ID {
+		t.Errorf("application owning its profile got profile %d, want %d", second.StudentProfileID, legacy.ID)
+	}
+}
+
+func TestStudentProfileToInputKeepsZeroValues(t *testing.T) {
+	input := StudentProfileToInput(&models.StudentProfile{FullName: "Asha Rao"})
+	if input.Income == nil || *input.Income != 0 {
+		t.Errorf("Income = %v, want a zero income", input.Income)
+	}
+	if input.DateOfBirth != nil {
+		t.Errorf("DateOfBirth = %v, want nil for a missing date of birth", input.DateOfBirth)
+	}
+
+	dob := time.Date(2004, time.May, 17, 0, 0, 0, 0, time.UTC)
+	input = StudentProfileToInput(&models.StudentProfile{DateOfBirth: &dob, Income: 120000})
+	if input.DateOfBirth == nil || !input.DateOfBirth.Equal(dob) {
+		t.Errorf("DateOfBirth = %v, want %s", input.DateOfBirth, dob)
+	}
+	if input.DateOfBirth == &dob {
+		t.Error("DateOfBirth shares the profile's date")
+	}
+	if input.Income == nil || *input.Income != 120000 {
+		t.Errorf("Income = %v, want 120000", input.Income)
+	}
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidScheme is returned when a scheme definition fails validation.
+var ErrInvalidScheme = errors.New("invalid scheme")
+
+// ValidateScheme checks a scheme and its eligibility criteria for consistency.
+//
+// Parameters:
+// - scheme (*models.Scheme): The scheme to validate, with Eligibility populated.
+//
+// Returns:
+// - error: An error wrapping ErrInvalidScheme, or nil if the scheme is valid.
+func ValidateScheme(scheme *models.Scheme) error {
+	if scheme.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidScheme)
+	}
+	if scheme.Amount < 0 {
+		return fmt.Errorf("%w: amount cannot be negative", ErrInvalidScheme)
+	}
+	if !scheme.EndDate.After(scheme.StartDate) {
+		return fmt.Errorf("%w: end date must be after start date", ErrInvalidScheme)
+	}
+	switch scheme.Status {
+	case models.SchemeStatusUpcoming, models.SchemeStatusOpen, models.SchemeStatusClosed:
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidScheme, scheme.Status)
+	}
+
+	e := scheme.Eligibility
+	if e.AgeMin < 0 || e.AgeMax < 0 {
+		return fmt.Errorf("%w: age limits cannot be negative", ErrInvalidScheme)
+	}
+	if e.AgeMax > 0 && e.AgeMin > e.AgeMax {
+		return fmt.Errorf("%w: minimum age is greater than maximum age", ErrInvalidScheme)
+	}
+	if e.IncomeLimit < 0 {
+		return fmt.Errorf("%w: income limit cannot be negative", ErrInvalidScheme)
+	}
+	switch e.Gender {
+	case "", models.GenderMale, models.GenderFemale, models.GenderOther:
+	default:
+		return fmt.Errorf("%w: unknown gender %q", ErrInvalidScheme, e.Gender)
+	}
+	switch e.Category {
+	case "", models.CategoryGeneral, models.CategorySC, models.CategoryST, models.CategoryOBC, models.CategoryOther:
+	default:
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidScheme, e.Category)
+	}
+	switch e.AcademicQualification {
+	case "", models.AcademicQualificationNone, models.AcademicQualificationClassX, models.AcademicQualificationClassXII,
+		models.AcademicQualificationDiploma, models.AcademicQualificationGraduate, models.AcademicQualificationPostGraduate:
+	default:
+		return fmt.Errorf("%w: unknown academic qualification %q", ErrInvalidScheme, e.AcademicQualification)
+	}
+	return nil
+}
+
+// ApplyEligibilityInput copies the criteria of an eligibility input onto an eligibility model.
+// Document mappings are handled separately by ReplaceEligibilityDocuments.
+func ApplyEligibilityInput(e *models.Eligibility, input models.EligibilityInput) {
+	e.Gender = input.Gender
+	e.AgeMin = input.AgeMin
+	e.AgeMax = input.AgeMax
+	e.IncomeLimit = input.IncomeLimit
+	e.AcademicQualification = input.AcademicQualification
+	e.Category = input.Category
+}
+
+// ApplySchemeInput copies the fields of a scheme input onto a scheme model, including eligibility criteria.
+// An empty status is derived from the scheme dates, see SchemeStatusAt.
+func ApplySchemeInput(scheme *models.Scheme, input models.SchemeInput) {
+	scheme.Name = input.Name
+	scheme.Description = input.Description
+	scheme.Amount = input.Amount
+	scheme.ApplicationLink = input.ApplicationLink
+	scheme.StartDate = input.StartDate
+	scheme.EndDate = input.EndDate
+	scheme.Status = input.Status
+	if scheme.Status == "" {
+		scheme.Status = SchemeStatusAt(scheme, time.Now())
+	}
+	ApplyEligibilityInput(&scheme.Eligibility, input.Eligibility)
+}
+
+// ApplySchemePatch copies only the non-nil fields of a patch input onto a scheme model.
+func ApplySchemePatch(scheme *models.Scheme, input models.SchemePatchInput) {
+	if input.Name != nil {
+		scheme.Name = *input.Name
+	}
+	if input.Description != nil {
+		scheme.Description = *input.Description
+	}
+	if input.Amount != nil {
+		scheme.Amount = *input.Amount
+	}
+	if input.ApplicationLink != nil {
+		scheme.ApplicationLink = *input.ApplicationLink
+	}
+	if input.StartDate != nil {
+		scheme.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		scheme.EndDate = *input.EndDate
+	}
+	if input.Status != nil {
+		scheme.Status = *input.Status
+	}
+
+	if input.Eligibility == nil {
+		return
+	}
+	e := &scheme.Eligibility
+	if input.Eligibility.Gender != nil {
+		e.Gender = *input.Eligibility.Gender
+	}
+	if input.Eligibility.AgeMin != nil {
+		e.AgeMin = *input.Eligibility.AgeMin
+	}
+	if input.Eligibility.AgeMax != nil {
+		e.AgeMax = *input.Eligibility.AgeMax
+	}
+	if input.Eligibility.IncomeLimit != nil {
+		e.IncomeLimit = *input.Eligibility.IncomeLimit
+	}
+	if input.Eligibility.AcademicQualification != nil {
+		e.AcademicQualification = *input.Eligibility.AcademicQualification
+	}
+	if input.Eligibility.Category != nil {
+		e.Category = *input.Eligibility.Category
+	}
+}
+
+// CreateScheme validates a scheme input and creates the scheme with its eligibility
+// criteria and document requirements in one transaction.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - input (models.SchemeInput): The scheme definition.
+//
+// Returns:
+// - *models.Scheme: The created scheme.
+// - error: An error wrapping ErrInvalidScheme for bad input, or a database error.
+func CreateScheme(tx *gorm.DB, input models.SchemeInput) (*models.Scheme, error) {
+	var scheme models.Scheme
+	if err := ReplaceScheme(tx, &scheme, input); err != nil {
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+// ReplaceScheme overwrites a scheme, its eligibility criteria and document requirements
+// with a scheme input in one transaction. A scheme without ID is created.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - scheme (*models.Scheme): The scheme to overwrite, with Eligibility loaded.
+// - input (models.SchemeInput): The new scheme definition.
+//
+// Returns:
+// - error: An error wrapping ErrInvalidScheme for bad input, or a database error.
+func ReplaceScheme(tx *gorm.DB, scheme *models.Scheme, input models.SchemeInput) error {
+	ApplySchemeInput(scheme, input)
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := ValidateScheme(scheme); err != nil {
+			return err
+		}
+		if err := SaveScheme(tx, scheme); err != nil {
+			return err
+		}
+		return ReplaceEligibilityDocuments(tx, scheme.EligibilityID, input.Eligibility.Documents)
+	})
+}
+
+// SchemeToInput converts a scheme into the input form used to create it, e.g. for export.
+// The scheme must be loaded with Eligibility.DocumentMappings.Document.
+func SchemeToInput(scheme *models.Scheme) models.SchemeInput {
+	e := scheme.Eligibility
+	documents := make([]models.EligibilityDocumentInput, 0, len(e.DocumentMappings))
+	for _, mapping := range e.DocumentMappings {
+		documents = append(documents, models.EligibilityDocumentInput{
+			Name:        mapping.Document.Name,
+			IsMandatory: mapping.IsMandatory,
+		})
+	}
+	return models.SchemeInput{
+		Name:            scheme.Name,
+		Description:     scheme.Description,
+		Amount:          scheme.Amount,
+		ApplicationLink: scheme.ApplicationLink,
+		StartDate:       scheme.StartDate,
+		EndDate:         scheme.EndDate,
+		Status:          scheme.Status,
+		Eligibility: models.EligibilityInput{
+			Gender:                e.Gender,
+			AgeMin:                e.AgeMin,
+			AgeMax:                e.AgeMax,
+			IncomeLimit:           e.IncomeLimit,
+			AcademicQualification: e.AcademicQualification,
+			Category:              e.Category,
+			Documents:             documents,
+		},
+	}
+}
+
+// SaveScheme creates or updates a scheme together with its eligibility row.
+// It must be called inside a transaction when used together with ReplaceEligibilityDocuments.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - scheme (*models.Scheme): The scheme to persist, with Eligibility populated.
+//
+// Returns:
+// - error: An error if the operation fails, or nil if successful.
+func SaveScheme(tx *gorm.DB, scheme *models.Scheme) error {
+	if err := tx.Omit("DocumentMappings").Save(&scheme.Eligibility).Error; err != nil {
+		return fmt.Errorf("failed to save eligibility: %w", err)
+	}
+	scheme.EligibilityID = scheme.Eligibility.ID
+	if err := tx.Omit("Eligibility").Save(scheme).Error; err != nil {
+		return fmt.Errorf("failed to save scheme: %w", err)
+	}
+	return nil
+}
+
+// ReplaceEligibilityDocuments replaces all document mappings of an eligibility with the given list.
+// Each document is resolved by its DocumentsRequired name; unknown names abort the operation.
+//
+// Parameters:
+// - tx (*gorm.DB): The database transaction.
+// - eligibilityID (uint): The ID of the eligibility whose mappings are replaced.
+// - documents ([]models.EligibilityDocumentInput): The document requirements to store.
+//
+// Returns:
+// - error: An error wrapping ErrInvalidScheme for bad input, or a database error.
+func ReplaceEligibilityDocuments(tx *gorm.DB, eligibilityID uint, documents []models.EligibilityDocumentInput) error {
+	if err := tx.Where("eligibility_id = ?", eligibilityID).Delete(&models.EligibilityDocumentMap{}).Error; err != nil {
+		return fmt.Errorf("failed to clear document mappings: %w", err)
+	}
+
+	seen := make(map[string]bool, len(documents))
+	for _, doc := range documents {
+		if seen[doc.Name] {
+			return fmt.Errorf("%w: document %q listed more than once", ErrInvalidScheme, doc.Name)
+		}
+		seen[doc.Name] = true
+
+		var required models.DocumentsRequired
+		if err := tx.Where("name = ?", doc.Name).First(&required).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: unknown document %q", ErrInvalidScheme, doc.Name)
+			}
+			return fmt.Errorf("error checking document: %w", err)
+		}
+
+		mapping := models.EligibilityDocumentMap{
+			EligibilityID: eligibilityID,
+			DocumentID:    required.ID,
+			IsMandatory:   doc.IsMandatory,
+		}
+		if err := tx.Omit("Eligibility", "Document").Create(&mapping).Error; err != nil {
+			return fmt.Errorf("failed to create document mapping: %w", err)
+		}
+	}
+	return nil
+}
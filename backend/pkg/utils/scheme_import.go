@@ -0,0 +1,446 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// SchemeFormat is a file format for scheme import and export.
+type SchemeFormat string
+
+const (
+	SchemeFormatJSON SchemeFormat = "json"
+	SchemeFormatYAML SchemeFormat = "yaml"
+	SchemeFormatCSV  SchemeFormat = "csv"
+)
+
+// MaxSchemeImportSize is the largest scheme file accepted by the import endpoint.
+const MaxSchemeImportSize = 5 << 20
+
+// ErrUnsupportedFormat is returned for an unknown scheme file format.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// errImportRollback rolls back an import that is a dry run or has failed rows.
+var errImportRollback = errors.New("import rolled back")
+
+// schemeCSVHeader lists the CSV columns. Document columns hold semicolon-separated names.
+var schemeCSVHeader = []string{
+	"name", "description", "amount", "application_link", "start_date", "end_date", "status",
+	"gender", "age_min", "age_max", "income_limit", "academic_qualification", "category",
+	"mandatory_documents", "optional_documents",
+}
+
+// SchemeRecord is one scheme definition read from an import file.
+// Err is set when the record itself could not be parsed.
+type SchemeRecord struct {
+	Row   int
+	Input models.SchemeInput
+	Err   error
+}
+
+// ParseSchemeFormat resolves a format name, a file name or a content type to a SchemeFormat.
+// "yml" is accepted as an alias for YAML.
+func ParseSchemeFormat(value string) (SchemeFormat, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.Index(v, ";"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	if ext := filepath.Ext(v); ext != "" {
+		v = ext[1:]
+	} else if i := strings.LastIndex(v, "/"); i >= 0 {
+		v = strings.TrimPrefix(v[i+1:], "x-")
+	}
+
+	switch v {
+	case "json":
+		return SchemeFormatJSON, nil
+	case "yaml", "yml":
+		return SchemeFormatYAML, nil
+	case "csv":
+		return SchemeFormatCSV, nil
+	}
+	return "", fmt.Errorf("%w %q, expected json, yaml or csv", ErrUnsupportedFormat, value)
+}
+
+// DecodeSchemes reads scheme definitions from a JSON array, a YAML sequence or a CSV file.
+// A record that cannot be parsed, e.g. a JSON object with unknown fields, is returned with Err set
+// so the remaining records are still read.
+//
+// Parameters:
+// - r (io.Reader): The file content.
+// - format (SchemeFormat): The file format.
+//
+// Returns:
+// - []SchemeRecord: The records in file order. Row is the position for JSON and YAML
+// and the line number for CSV.
+// - error: An error if the file as a whole is malformed.
+func DecodeSchemes(r io.Reader, format SchemeFormat) ([]SchemeRecord, error) {
+	switch format {
+	case SchemeFormatJSON:
+		var items []json.RawMessage
+		if err := json.NewDecoder(r).Decode(&items); err != nil {
+			return nil, fmt.Errorf("expected a JSON array of schemes: %w", err)
+		}
+		records := make([]SchemeRecord, len(items))
+		for i, item := range items {
+			records[i].Row = i + 1
+			decoder := json.NewDecoder(bytes.NewReader(item))
+			decoder.DisallowUnknownFields()
+			records[i].Err = decoder.Decode(&records[i].Input)
+		}
+		return records, nil
+
+	case SchemeFormatYAML:
+		var items []yaml.Node
+		if err := yaml.NewDecoder(r).Decode(&items); err != nil {
+			return nil, fmt.Errorf("expected a YAML sequence of schemes: %w", err)
+		}
+		records := make([]SchemeRecord, len(items))
+		for i := range items {
+			records[i].Row = i + 1
+			records[i].Err = items[i].Decode(&records[i].Input)
+		}
+		return records, nil
+
+	case SchemeFormatCSV:
+		return decodeSchemeCSV(r)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+}
+
+// EncodeSchemes writes scheme definitions in the given format. The output can be read back by DecodeSchemes.
+// CSV cells that a spreadsheet would evaluate as formulas are escaped with EscapeCSVCell.
+func EncodeSchemes(w io.Writer, format SchemeFormat, inputs []models.SchemeInput) error {
+	switch format {
+	case SchemeFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inputs)
+
+	case SchemeFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(inputs); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	case SchemeFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(schemeCSVHeader); err != nil {
+			return err
+		}
+		for _, input := range inputs {
+			if err := writer.Write(EscapeCSVRow(schemeCSVRow(input))); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+}
+
+func schemeCSVRow(input models.SchemeInput) []string {
+	var mandatory, optional []string
+	for _, doc := range input.Eligibility.Documents {
+		if doc.IsMandatory {
+			mandatory = append(mandatory, doc.Name)
+		} else {
+			optional = append(optional, doc.Name)
+		}
+	}
+	e := input.Eligibility
+	return []string{
+		input.Name,
+		input.Description,
+		strconv.FormatFloat(input.Amount, 'f', -1, 64),
+		input.ApplicationLink,
+		input.StartDate.Format(time.RFC3339),
+		input.EndDate.Format(time.RFC3339),
+		input.Status,
+		string(e.Gender),
+		strconv.Itoa(e.AgeMin),
+		strconv.Itoa(e.AgeMax),
+		strconv.FormatFloat(e.IncomeLimit, 'f', -1, 64),
+		string(e.AcademicQualification),
+		string(e.Category),
+		strings.Join(mandatory, ";"),
+		strings.Join(optional, ";"),
+	}
+}
+
+// decodeSchemeCSV reads a CSV file whose first line names the columns. Columns may appear
+// in any order and all but name are optional.
+func decodeSchemeCSV(r io.Reader) ([]SchemeRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !containsString(schemeCSVHeader, name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("CSV header has no name column")
+	}
+
+	var records []SchemeRecord
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			records = append(records, SchemeRecord{Row: parseErr.StartLine, Err: err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(fields) != len(header) {
+			records = append(records, SchemeRecord{
+				Row: line,
+				Err: fmt.Errorf("expected %d fields, got %d", len(header), len(fields)),
+			})
+			continue
+		}
+
+		input, err := parseSchemeCSVRow(func(name string) string {
+			if i, ok := columns[name]; ok {
+				return UnescapeCSVCell(strings.TrimSpace(fields[i]))
+			}
+			return ""
+		})
+		records = append(records, SchemeRecord{Row: line, Input: input, Err: err})
+	}
+	return records, nil
+}
+
+func parseSchemeCSVRow(field func(name string) string) (models.SchemeInput, error) {
+	input := models.SchemeInput{
+		Name:            field("name"),
+		Description:     field("description"),
+		ApplicationLink: field("application_link"),
+		Status:          field("status"),
+		Eligibility: models.EligibilityInput{
+			Gender:                models.Gender(field("gender")),
+			AcademicQualification: models.AcademicQualification(field("academic_qualification")),
+			Category:              models.Category(field("category")),
+		},
+	}
+
+	var err error
+	if input.Amount, err = parseCSVFloat(field, "amount"); err != nil {
+		return input, err
+	}
+	if input.StartDate, err = parseCSVDate(field, "start_date"); err != nil {
+		return input, err
+	}
+	if input.EndDate, err = parseCSVDate(field, "end_date"); err != nil {
+		return input, err
+	}
+	if input.Eligibility.AgeMin, err = parseCSVInt(field, "age_min"); err != nil {
+		return input, err
+	}
+	if input.Eligibility.AgeMax, err = parseCSVInt(field, "age_max"); err != nil {
+		return input, err
+	}
+	if input.Eligibility.IncomeLimit, err = parseCSVFloat(field, "income_limit"); err != nil {
+		return input, err
+	}
+
+	for _, column := range []string{"mandatory_documents", "optional_documents"} {
+		for _, name := range strings.Split(field(column), ";") {
+			if name = strings.TrimSpace(name); name != "" {
+				input.Eligibility.Documents = append(input.Eligibility.Documents, models.EligibilityDocumentInput{
+					Name:        name,
+					IsMandatory: column == "mandatory_documents",
+				})
+			}
+		}
+	}
+	return input, nil
+}
+
+func parseCSVFloat(field func(string) string, name string) (float64, error) {
+	value := field(name)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number %q", name, value)
+	}
+	return f, nil
+}
+
+func parseCSVInt(field func(string) string, name string) (int, error) {
+	value := field(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid integer %q", name, value)
+	}
+	return n, nil
+}
+
+// parseCSVDate accepts RFC 3339 timestamps and plain YYYY-MM-DD dates.
+func parseCSVDate(field func(string) string, name string) (time.Time, error) {
+	value := field(name)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s is required", name)
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: expected YYYY-MM-DD or RFC 3339, got %q", name, value)
+	}
+	return t, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UpsertScheme creates a scheme, or replaces the scheme with the same name.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - input (models.SchemeInput): The scheme definition.
+//
+// Returns:
+// - *models.Scheme: The created or updated scheme.
+// - bool: Whether the scheme was created.
+// - error: An error wrapping ErrInvalidScheme for bad input or an ambiguous name, or a database error.
+func UpsertScheme(tx *gorm.DB, input models.SchemeInput) (*models.Scheme, bool, error) {
+	if input.Name == "" {
+		return nil, false, fmt.Errorf("%w: name is required", ErrInvalidScheme)
+	}
+
+	var matches []models.Scheme
+	if err := tx.Preload("Eligibility").Where("name = ?", input.Name).Limit(2).Find(&matches).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to look up scheme: %w", err)
+	}
+	switch len(matches) {
+	case 0:
+		scheme, err := CreateScheme(tx, input)
+		return scheme, true, err
+	case 1:
+		scheme := &matches[0]
+		return scheme, false, ReplaceScheme(tx, scheme, input)
+	default:
+		return nil, false, fmt.Errorf("%w: more than one scheme is named %q", ErrInvalidScheme, input.Name)
+	}
+}
+
+// ImportSchemes upserts scheme records by name in one transaction. Every record is
+// attempted so the report lists all failures; nothing is saved if any record fails
+// or if dryRun is set.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - records ([]SchemeRecord): The decoded records.
+// - dryRun (bool): Validate every record and roll back.
+//
+// Returns:
+// - models.SchemeImportReport: The outcome of every record.
+// - error: A database error that aborted the whole import, e.g. a failed commit.
+func ImportSchemes(tx *gorm.DB, records []SchemeRecord, dryRun bool) (models.SchemeImportReport, error) {
+	report := models.SchemeImportReport{
+		DryRun: dryRun,
+		Rows:   make([]models.SchemeImportRow, 0, len(records)),
+	}
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[string]int, len(records))
+		for _, record := range records {
+			row := models.SchemeImportRow{Row: record.Row, Name: record.Input.Name}
+
+			err := record.Err
+			if err == nil {
+				if previous, ok := seen[record.Input.Name]; ok {
+					err = fmt.Errorf("%w: name %q already used by row %d", ErrInvalidScheme, record.Input.Name, previous)
+				} else {
+					seen[record.Input.Name] = record.Row
+				}
+			}
+			if err == nil {
+				// Each record runs in a savepoint so a failed record does not abort the transaction.
+				err = tx.Transaction(func(tx *gorm.DB) error {
+					scheme, created, err := UpsertScheme(tx, record.Input)
+					if err != nil {
+						return err
+					}
+					if created {
+						row.Action = models.SchemeImportCreated
+						if !dryRun {
+							row.SchemeID = scheme.ID
+						}
+					} else {
+						row.Action = models.SchemeImportUpdated
+						row.SchemeID = scheme.ID
+					}
+					return nil
+				})
+			}
+
+			switch {
+			case err != nil:
+				row.Action = models.SchemeImportFailed
+				row.SchemeID = 0
+				row.Error = err.Error()
+				report.Failed++
+			case row.Action == models.SchemeImportCreated:
+				report.Created++
+			default:
+				report.Updated++
+			}
+			report.Rows = append(report.Rows, row)
+		}
+
+		if dryRun || report.Failed > 0 {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return report, err
+	}
+	report.Saved = err == nil
+	return report, nil
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestEncodeSchemesCSVEscapesFormulas(t *testing.T) {
+	input := models.SchemeInput{
+		Name:        "=cmd|' /C calc'!A0",
+		Description: "@SUM(1+1)",
+		Amount:      5000,
+		StartDate:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Eligibility: models.EligibilityInput{AgeMin: 18, AgeMax: 30},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSchemes(&buf, SchemeFormatCSV, []models.SchemeInput{input}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range rows[1] {
+		if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+			t.Errorf("exported cell %q starts a formula", cell)
+		}
+	}
+
+	records, err := DecodeSchemes(&buf, SchemeFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Err != nil {
+		t.Fatalf("DecodeSchemes() = %+v", records)
+	}
+	if got := records[0].Input; got.Name != input.Name || got.Description != input.Description {
+		t.Errorf("round trip = %q, %q, want %q, %q", got.Name, got.Description, input.Name, input.Description)
+	}
+}
+
+func TestDecodeSchemesJSONRejectsUnknownFields(t *testing.T) {
+	file := `[
+		{"name": "Merit", "start_date": "2024-01-01T00:00:00Z", "end_date": "2024-12-31T00:00:00Z"},
+		{"name": "Typo", "start_date": "2024-01-01T00:00:00Z", "end_date": "2024-12-31T00:00:00Z", "eligibilty": {"age_min": 18}}
+	]`
+	records, err := DecodeSchemes(strings.NewReader(file), SchemeFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("DecodeSchemes() returned %d records, want 2", len(records))
+	}
+	if records[0].Err != nil {
+		t.Errorf("row 1 error = %v", records[0].Err)
+	}
+	if records[1].Err == nil || !strings.Contains(records[1].Err.Error(), "eligibilty") {
+		t.Errorf("row 2 error = %v, want an unknown field error", records[1].Err)
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrSchemeNotOpen is returned when an application is started or submitted for a scheme
+// that is not accepting applications.
+var ErrSchemeNotOpen = errors.New("scheme is not open for applications")
+
+// SchemeStatusAt returns the status a scheme should have at the given time based on its dates:
+// upcoming before StartDate, open until EndDate and closed afterwards.
+func SchemeStatusAt(scheme *models.Scheme, now time.Time) string {
+	switch {
+	case now.Before(scheme.StartDate):
+		return models.SchemeStatusUpcoming
+	case now.Before(scheme.EndDate):
+		return models.SchemeStatusOpen
+	default:
+		return models.SchemeStatusClosed
+	}
+}
+
+// CheckSchemeOpen verifies that a scheme accepts applications. The status is authoritative,
+// so admins can open or close a scheme early, but a scheme past its end date is never open
+// even if the scheduler has not closed it yet.
+//
+// Parameters:
+// - scheme (*models.Scheme): The scheme to check.
+// - now (time.Time): The current time.
+//
+// Returns:
+// - error: An error wrapping ErrSchemeNotOpen, or nil if the scheme is open.
+func CheckSchemeOpen(scheme *models.Scheme, now time.Time) error {
+	if scheme.Status != models.SchemeStatusOpen {
+		return fmt.Errorf("%w: scheme %q is %s", ErrSchemeNotOpen, scheme.Name, scheme.Status)
+	}
+	if !now.Before(scheme.EndDate) {
+		return fmt.Errorf("%w: scheme %q closed on %s", ErrSchemeNotOpen, scheme.Name, scheme.EndDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// AdvanceSchemeStatuses moves schemes forward through upcoming, open and closed according
+// to their dates. Schemes never move backwards, so a scheme closed early stays closed.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - now (time.Time): The current time.
+//
+// Returns:
+// - int64: The number of schemes opened.
+// - int64: The number of schemes closed.
+// - error: A database error, or nil if successful.
+func AdvanceSchemeStatuses(tx *gorm.DB, now time.Time) (int64, int64, error) {
+	var opened, closed int64
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Scheme{}).
+			Where("status = ? AND start_date <= ? AND end_date > ?", models.SchemeStatusUpcoming, now, now).
+			Update("status", models.SchemeStatusOpen)
+		if result.Error != nil {
+			return fmt.Errorf("failed to open schemes: %w", result.Error)
+		}
+		opened = result.RowsAffected
+
+		result = tx.Model(&models.Scheme{}).
+			Where("status <> ? AND end_date <= ?", models.SchemeStatusClosed, now).
+			Update("status", models.SchemeStatusClosed)
+		if result.Error != nil {
+			return fmt.Errorf("failed to close schemes: %w", result.Error)
+		}
+		closed = result.RowsAffected
+		return nil
+	})
+	return opened, closed, err
+}
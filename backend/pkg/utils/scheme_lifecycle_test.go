@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/db/dbtest"
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestSchemeStatusAt(t *testing.T) {
+	start := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	scheme := &models.Scheme{StartDate: start, EndDate: start.AddDate(0, 1, 0)}
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{start.Add(-time.Second), models.SchemeStatusUpcoming},
+		{start, models.SchemeStatusOpen},
+		{scheme.EndDate.Add(-time.Second), models.SchemeStatusOpen},
+		{scheme.EndDate, models.SchemeStatusClosed},
+	}
+	for _, tt := range tests {
+		if got := SchemeStatusAt(scheme, tt.now); got != tt.want {
+			t.Errorf("SchemeStatusAt(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSchemeOpen(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status string
+		end    time.Time
+		open   bool
+	}{
+		{"open", models.SchemeStatusOpen, now.AddDate(0, 1, 0), true},
+		{"open past its end date", models.SchemeStatusOpen, now, false},
+		{"upcoming", models.SchemeStatusUpcoming, now.AddDate(0, 1, 0), false},
+		{"closed early", models.SchemeStatusClosed, now.AddDate(0, 1, 0), false},
+	}
+	for _, tt := range tests {
+		err := CheckSchemeOpen(&models.Scheme{Name: tt.name, Status: tt.status, EndDate: tt.end}, now)
+		if (err == nil) != tt.open || (err != nil && !errors.Is(err, ErrSchemeNotOpen)) {
+			t.Errorf("%s: CheckSchemeOpen = %v, want open %v", tt.name, err, tt.open)
+		}
+	}
+}
+
+func TestAdvanceSchemeStatuses(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	now := time.Now()
+
+	create := func(name, status string, start, end time.Time) *models.Scheme {
+		t.Helper()
+		scheme, err := CreateScheme(conn, models.SchemeInput{
+			Name:        name,
+			StartDate:   start,
+			EndDate:     end,
+			Status:      status,
+			Eligibility: models.EligibilityInput{Category: models.CategoryGeneral},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scheme
+	}
+	schemes := map[*models.Scheme]string{
+		create("started", models.SchemeStatusUpcoming, now.AddDate(0, 0, -1), now.AddDate(0, 1, 0)):          models.SchemeStatusOpen,
+		create("not started", models.SchemeStatusUpcoming, now.AddDate(0, 0, 1), now.AddDate(0, 1, 0)):       models.SchemeStatusUpcoming,
+		create("missed entirely", models.SchemeStatusUpcoming, now.AddDate(0, -2, 0), now.AddDate(0, -1, 0)): models.SchemeStatusClosed,
+		create("ended", models.SchemeStatusOpen, now.AddDate(0, -2, 0), now.AddDate(0, 0, -1)):               models.SchemeStatusClosed,
+		create("running", models.SchemeStatusOpen, now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)):              models.SchemeStatusOpen,
+		create("closed early", models.SchemeStatusClosed, now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)):       models.SchemeStatusClosed,
+	}
+
+	opened, closed, err := AdvanceSchemeStatuses(conn, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened != 1 || closed != 2 {
+		t.Errorf("AdvanceSchemeStatuses = %d opened, %d closed, want 1 and 2", opened, closed)
+	}
+	for scheme, want := range schemes {
+		var stored models.Scheme
+		if err := conn.First(&stored, scheme.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != want {
+			t.Errorf("scheme %q is %s, want %s", scheme.Name, stored.Status, want)
+		}
+	}
+
+	if opened, closed, err := AdvanceSchemeStatuses(conn, now); err != nil || opened != 0 || closed != 0 {
+		t.Errorf("second run = %d opened, %d closed, %v, want nothing to do", opened, closed, err)
+	}
+}
+
+func TestInitApplicationRequiresOpenScheme(t *testing.T) {
+	conn := dbtest.Migrated(t)
+	now := time.Now()
+
+	user, err := CreateUser(conn, "asha", "asha@example.org", "correct horse", models.RoleApplicant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheme, err := CreateScheme(conn, models.SchemeInput{
+		Name:        "Merit scholarship",
+		StartDate:   now.AddDate(0, 0, 1),
+		EndDate:     now.AddDate(0, 1, 0),
+		Status:      models.SchemeStatusUpcoming,
+		Eligibility: models.EligibilityInput{Category: models.CategoryGeneral},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := InitApplication(conn, user.ID, scheme.ID, nil); !errors.Is(err, ErrSchemeNotOpen) {
+		t.Errorf("InitApplication for an upcoming scheme = %v, want ErrSchemeNotOpen", err)
+	}
+	if err := conn.Model(scheme).Update("status", models.SchemeStatusOpen).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InitApplication(conn, user.ID, scheme.ID, nil); err != nil {
+		t.Errorf("InitApplication for an open scheme = %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// BuildApplicationSnapshot collects the current profile, documents, addresses, education history
+// and scheme definition of an application into snapshot data.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - app (*models.Application): The application; its profile and scheme are loaded from tx.
+//
+// Returns:
+// - *models.ApplicationSnapshotData: The snapshot data.
+// - error: A database error if the profile or scheme cannot be loaded.
+func BuildApplicationSnapshot(tx *gorm.DB, app *models.Application) (*models.ApplicationSnapshotData, error) {
+	var profile models.StudentProfile
+	if err := PreloadStudentProfile(tx).First(&profile, app.StudentProfileID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load student profile: %w", err)
+	}
+	var scheme models.Scheme
+	if err := tx.Preload("Eligibility.DocumentMappings.Document").First(&scheme, app.SchemeID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load scheme: %w", err)
+	}
+
+	data := &models.ApplicationSnapshotData{
+		FormatVersion: models.SnapshotFormatVersion,
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		SubmittedAt:   app.SubmittedAt,
+		Profile: models.ProfileSnapshot{
+			FullName:         profile.FullName,
+			Gender:           profile.Gender,
+			PhoneNumber:      profile.PhoneNumber,
+			Qualification:    profile.Qualification,
+			Email:            profile.Email,
+			AadhaarNumber:    profile.AadhaarNumber,
+			Nationality:      profile.Nationality,
+			Category:         profile.Category,
+			Income:           profile.Income,
+			IsInternational:  profile.IsInternational,
+			Documents:        make([]models.DocumentSnapshot, 0, len(profile.Documents)),
+			Addresses:        make(map[string]models.AddressInput, len(profile.Addresses)),
+			EducationHistory: make([]models.EducationHistoryInput, 0, len(profile.EducationHistory)),
+		},
+		Scheme: models.SchemeSnapshot{ID: scheme.ID, SchemeInput: SchemeToInput(&scheme)},
+	}
+	if profile.DateOfBirth != nil {
+		data.Profile.DateOfBirth = *profile.DateOfBirth
+	}
+	for _, doc := range profile.Documents {
+		document := models.DocumentSnapshot{
+			ID:                 doc.ID,
+			Name:               doc.Name,
+			StorageKey:         doc.StorageKey,
+			ContentType:        doc.ContentType,
+			Size:               doc.Size,
+			Checksum:           doc.Checksum,
+			VerificationStatus: doc.VerificationStatus,
+			RejectionReason:    doc.RejectionReason,
+		}
+		if doc.StorageKey == "" {
+			document.URL = doc.URL
+		}
+		data.Profile.Documents = append(data.Profile.Documents, document)
+	}
+	sort.Slice(data.Profile.Documents, func(i, j int) bool {
+		return data.Profile.Documents[i].ID < data.Profile.Documents[j].ID
+	})
+	for _, addr := range profile.Addresses {
+		data.Profile.Addresses[addr.Type] = models.AddressInput{
+			Type:    addr.Type,
+			Street:  addr.Street,
+			City:    addr.City,
+			State:   addr.State,
+			Pincode: addr.Pincode,
+			Country: addr.Country,
+		}
+	}
+	for _, edu := range profile.EducationHistory {
+		data.Profile.EducationHistory = append(data.Profile.EducationHistory, models.EducationHistoryInput{
+			Degree:        edu.Degree,
+			University:    edu.University,
+			YearOfPassing: edu.YearOfPassing,
+			Grade:         edu.Grade,
+			Course:        edu.Course,
+		})
+	}
+	sort.SliceStable(data.Profile.EducationHistory, func(i, j int) bool {
+		return data.Profile.EducationHistory[i].YearOfPassing < data.Profile.EducationHistory[j].YearOfPassing
+	})
+	return data, nil
+}
+
+// CreateApplicationSnapshot stores the next snapshot version of an application.
+//
+// Parameters:
+// - tx (*gorm.DB): The database transaction the submission runs in.
+// - app (*models.Application): The submitted application.
+// - actorID (uint): The ID of the user submitting.
+//
+// Returns:
+// - *models.ApplicationSnapshot: The stored snapshot.
+// - error: An error if the snapshot cannot be built or stored.
+func CreateApplicationSnapshot(tx *gorm.DB, app *models.Application, actorID uint) (*models.ApplicationSnapshot, error) {
+	data, err := BuildApplicationSnapshot(tx, app)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	var latest int
+	if err := tx.Model(&models.ApplicationSnapshot{}).
+		Where("application_id = ?", app.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return nil, fmt.Errorf("failed to read snapshot version: %w", err)
+	}
+
+	snapshot := models.ApplicationSnapshot{
+		ApplicationID: app.ID,
+		Version:       latest + 1,
+		SubmittedBy:   actorID,
+		Data:          raw,
+	}
+	if err := tx.Create(&snapshot).Error; err != nil {
+		return nil, fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// snapshotDocumentPath selects the documents of a snapshot that are stored under $key.
+const snapshotDocumentPath = `$.profile.documents[*] ? (@.storage_key == $key)`
+
+// FindSnapshotDocument looks up the newest snapshot document stored under a storage key.
+// Snapshots keep the files of a submission after the applicant replaces or removes them.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - key (string): The storage key of the file.
+//
+// Returns:
+// - *models.DocumentSnapshot: The document as it was submitted.
+// - error: gorm.ErrRecordNotFound if no snapshot lists the file, or a database error.
+func FindSnapshotDocument(tx *gorm.DB, key string) (*models.DocumentSnapshot, error) {
+	// The path is bound as a parameter because its filter uses the ? placeholder character
+	var raw []byte
+	err := tx.Raw(`SELECT jsonb_path_query(data, ?::jsonpath, jsonb_build_object('key', ?::text))
+		FROM application_snapshots ORDER BY id DESC LIMIT 1`, snapshotDocumentPath, key).Row().Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up snapshot document: %w", err)
+	}
+	var document models.DocumentSnapshot
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot document: %w", err)
+	}
+	return &document, nil
+}
+
+// DiffJSON compares two JSON documents and lists their differences in JSON Patch style.
+// Objects are compared key by key and arrays element by element.
+//
+// Parameters:
+// - from (json.RawMessage): The older document.
+// - to (json.RawMessage): The newer document.
+//
+// Returns:
+// - []models.SnapshotChange: The changes ordered by path; empty if the documents are equal.
+// - error: An error if either document is not valid JSON.
+func DiffJSON(from, to json.RawMessage) ([]models.SnapshotChange, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(from, &a); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := json.Unmarshal(to, &b); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	changes := []models.SnapshotChange{}
+	diffValues("", a, b, &changes)
+	return changes, nil
+}
+
+func diffValues(path string, a, b interface{}, changes *[]models.SnapshotChange) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				child := path + "/" + escapePointer(k)
+				x, inA := av[k]
+				y, inB := bv[k]
+				switch {
+				case !inB:
+					*changes = append(*changes, models.SnapshotChange{Op: "remove", Path: child, OldValue: x})
+				case !inA:
+					*changes = append(*changes, models.SnapshotChange{Op: "add", Path: child, Value: y})
+				default:
+					diffValues(child, x, y, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				child := path + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(bv):
+					*changes = append(*changes, models.SnapshotChange{Op: "remove", Path: child, OldValue: av[i]})
+				case i >= len(av):
+					*changes = append(*changes, models.SnapshotChange{Op: "add", Path: child, Value: bv[i]})
+				default:
+					diffValues(child, av[i], bv[i], changes)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, models.SnapshotChange{Op: "replace", Path: path, OldValue: a, Value: b})
+	}
+}
+
+// escapePointer escapes a key for use in a JSON Pointer (RFC 6901).
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CanTransitionApplication checks whether an application may move to a new state.
+// Legacy rows without a status are treated as drafts.
+func CanTransitionApplication(app *models.Application, to models.ApplicationStatus, by models.Permission) error {
+	from := app.Status
+	if from == "" {
+		from = models.ApplicationStatusDraft
+	}
+	return models.ValidateTransition(from, to, by)
+}
+
+// TransitionApplication moves an application to a new state after validating the transition.
+// It keeps IsDraft and SubmittedAt consistent with the new state, gives a submitted application its
+// own copy of the applicant's saved profile and a new snapshot version, saves the application and
+// appends the change to the status history in one transaction. The row is locked and its stored
+// status compared with app.Status first, so a change made concurrently by someone else fails
+// instead of being overwritten.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - app (*models.Application): The application to move.
+// - to (models.ApplicationStatus): The requested state.
+// - by (models.Permission): The permission the actor is acting under.
+// - actorID (uint): The ID of the user making the change.
+// - reason (string): Why the transition was made; required for rejections and information requests.
+//
+// Returns:
+// - error: An error wrapping models.ErrIllegalTransition or models.ErrReasonRequired, or a database error.
+func TransitionApplication(tx *gorm.DB, app *models.Application, to models.ApplicationStatus, by models.Permission, actorID uint, reason string) error {
+	if err := CanTransitionApplication(app, to, by); err != nil {
+		return err
+	}
+	if to.RequiresReason() && strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: a reason is required to move an application to %q", models.ErrReasonRequired, to)
+	}
+
+	from := app.Status
+	app.Status = to
+	app.StatusReason = reason
+	app.IsDraft = to.IsEditable()
+	switch to {
+	case models.ApplicationStatusSubmitted:
+		now := time.Now()
+		app.SubmittedAt = &now
+	case models.ApplicationStatusWithdrawn:
+		app.SubmittedAt = nil
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		var current models.Application
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			First(&current, app.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock application: %w", err)
+		}
+		if current.Status != from {
+			return fmt.Errorf("%w: application changed from %q to %q in the meantime", models.ErrIllegalTransition, from, current.Status)
+		}
+		if to == models.ApplicationStatusSubmitted {
+			if err := SnapshotApplicationProfile(tx, app); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("User", "Scheme", "StudentProfile").Save(app).Error; err != nil {
+			return fmt.Errorf("failed to update application status: %w", err)
+		}
+		if to == models.ApplicationStatusSubmitted {
+			if _, err := CreateApplicationSnapshot(tx, app, actorID); err != nil {
+				return err
+			}
+		}
+		return RecordStatusChange(tx, app.ID, from, to, actorID, reason)
+	})
+}
+
+// RecordStatusChange appends an entry to an application's status history.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection or transaction.
+// - applicationID (uint): The ID of the application.
+// - from (models.ApplicationStatus): The previous state; empty when the application is created.
+// - to (models.ApplicationStatus): The new state.
+// - actorID (uint): The ID of the user making the change.
+// - reason (string): Why the change was made.
+//
+// Returns:
+// - error: An error if the entry cannot be stored.
+func RecordStatusChange(tx *gorm.DB, applicationID uint, from, to models.ApplicationStatus, actorID uint, reason string) error {
+	entry := models.ApplicationStatusHistory{
+		ApplicationID: applicationID,
+		FromStatus:    from,
+		ToStatus:      to,
+		ActorID:       actorID,
+		Reason:        reason,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestTransitionApplicationRejectsBeforeSaving(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   models.ApplicationStatus
+		to     models.ApplicationStatus
+		by     models.Permission
+		reason string
+		want   error
+	}{
+		{"rejection without reason", models.ApplicationStatusUnderReview, models.ApplicationStatusRejected,
+			models.PermissionApplicationReview, "", models.ErrReasonRequired},
+		{"information request with blank reason", models.ApplicationStatusUnderReview, models.ApplicationStatusNeedsInfo,
+			models.PermissionApplicationReview, "  ", models.ErrReasonRequired},
+		{"illegal move with reason", models.ApplicationStatusDraft, models.ApplicationStatusRejected,
+			models.PermissionApplicationReview, "incomplete", models.ErrIllegalTransition},
+		{"illegal move without reason", models.ApplicationStatusSubmitted, models.ApplicationStatusRejected,
+			models.PermissionApplicationReview, "", models.ErrIllegalTransition},
+		{"wrong permission", models.ApplicationStatusUnderReview, models.ApplicationStatusApproved,
+			models.PermissionApplicationOwn, "", models.ErrIllegalTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := models.Application{Status: tt.from}
+			// The transition is refused before the database is touched, so no connection is needed.
+			err := TransitionApplication(nil, &app, tt.to, tt.by, 1, tt.reason)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("TransitionApplication = %v, want %v", err, tt.want)
+			}
+			if errors.Is(err, models.ErrReasonRequired) && errors.Is(err, models.ErrIllegalTransition) {
+				t.Fatal("a missing reason is reported as an illegal transition")
+			}
+			if app.Status != tt.from {
+				t.Errorf("status changed to %s on a refused transition", app.Status)
+			}
+		})
+	}
+}
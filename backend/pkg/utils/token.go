@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// AccessTokenTTL is the lifetime of a signed access token.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is the lifetime of an opaque refresh token.
+	RefreshTokenTTL = 7 * 24 * time.Hour
+
+	tokenIssuer = "beneficiary-manager"
+
+	// MinJWTSecretLength is the minimum length of JWT_SECRET; HS256 needs at least 256 bits of key.
+	MinJWTSecretLength = 32
+	// placeholderSecret is the value shipped in the example configuration.
+	placeholderSecret = "change-me"
+)
+
+// ErrInvalidAccessToken is returned when an access token cannot be verified.
+var ErrInvalidAccessToken = errors.New("invalid access token")
+
+// AccessClaims are the claims carried by an access token.
+type AccessClaims struct {
+	Username string      `json:"username"`
+	Role     models.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the user ID stored in the subject claim.
+func (c *AccessClaims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad subject", ErrInvalidAccessToken)
+	}
+	return uint(id), nil
+}
+
+// CheckJWTSecret verifies that JWT_SECRET is set to a usable value. The server calls it at
+// startup so a missing or placeholder secret stops it before any token is issued.
+//
+// Returns:
+// - error: An error describing why the secret is rejected, or nil.
+func CheckJWTSecret() error {
+	_, err := jwtSecret()
+	return err
+}
+
+func jwtSecret() ([]byte, error) {
+	secret := os.Getenv("JWT_SECRET")
+	switch {
+	case secret == "":
+		return nil, errors.New("JWT_SECRET is not configured")
+	case secret == placeholderSecret:
+		return nil, errors.New("JWT_SECRET is still set to the placeholder value")
+	case len(secret) < MinJWTSecretLength:
+		return nil, fmt.Errorf("JWT_SECRET must be at least %d characters", MinJWTSecretLength)
+	}
+	return []byte(secret), nil
+}
+
+// IssueAccessToken signs a short-lived HS256 access token for a user.
+//
+// Parameters:
+// - user (*models.User): The authenticated user.
+//
+// Returns:
+// - string: The signed token.
+// - time.Time: The expiry time of the token.
+// - error: An error if signing fails.
+func IssueAccessToken(user *models.User) (string, time.Time, error) {
+	secret, err := jwtSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL)
+	claims := AccessClaims{
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken verifies the signature, issuer and expiry of an access token.
+//
+// Parameters:
+// - token (string): The signed token.
+//
+// Returns:
+// - *AccessClaims: The verified claims.
+// - error: An error wrapping ErrInvalidAccessToken if verification fails.
+func ParseAccessToken(token string) (*AccessClaims, error) {
+	secret, err := jwtSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	var claims AccessClaims
+	_, err = jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccessToken, err)
+	}
+	return &claims, nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+func TestCheckJWTSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		ok     bool
+	}{
+		{"empty", "", false},
+		{"placeholder", "change-me", false},
+		{"too short", strings.Repeat("x", MinJWTSecretLength-1), false},
+		{"long enough", strings.Repeat("x", MinJWTSecretLength), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", tt.secret)
+			if err := CheckJWTSecret(); (err == nil) != tt.ok {
+				t.Errorf("CheckJWTSecret() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIssueAccessTokenRefusesWeakSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", "change-me")
+	if _, _, err := IssueAccessToken(&models.User{ID: 1}); err == nil {
+		t.Fatal("IssueAccessToken signed a token with the placeholder secret")
+	}
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	user := &models.User{ID: 42, Username: "asha", Role: models.RoleReviewer}
+	token, expiresAt, err := IssueAccessToken(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl := time.Until(expiresAt); ttl <= 0 || ttl > AccessTokenTTL {
+		t.Errorf("token expires in %v, want within %v", ttl, AccessTokenTTL)
+	}
+
+	claims, err := ParseAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := claims.UserID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != user.ID || claims.Username != user.Username || claims.Role != user.Role {
+		t.Errorf("claims = %d %s %s, want %d %s %s", id, claims.Username, claims.Role, user.ID, user.Username, user.Role)
+	}
+}
+
+func TestParseAccessTokenRejects(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	sign := func(method jwt.SigningMethod, key interface{}, mutate func(*AccessClaims)) string {
+		now := time.Now()
+		claims := AccessClaims{
+			Username: "asha",
+			Role:     models.RoleApplicant,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    tokenIssuer,
+				Subject:   "42",
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+			},
+		}
+		if mutate != nil {
+			mutate(&claims)
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := sign(jwt.SigningMethodHS256, []byte(testJWTSecret), nil)
+
+	tests := map[string]string{
+		"expired": sign(jwt.SigningMethodHS256, []byte(testJWTSecret), func(c *AccessClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		}),
+		"no expiry": sign(jwt.SigningMethodHS256, []byte(testJWTSecret), func(c *AccessClaims) {
+			c.ExpiresAt = nil
+		}),
+		"wrong issuer": sign(jwt.SigningMethodHS256, []byte(testJWTSecret), func(c *AccessClaims) {
+			c.Issuer = "someone-else"
+		}),
+		"wrong secret":  sign(jwt.SigningMethodHS256, []byte(strings.Repeat("y", 32)), nil),
+		"wrong method":  sign(jwt.SigningMethodHS512, []byte(testJWTSecret), nil),
+		"unsigned":      sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, nil),
+		"tampered":      valid[:len(valid)-2] + "AA",
+		"not a token":   "not-a-token",
+		"empty":         "",
+		"extra segment": valid + ".x",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseAccessToken(token); !errors.Is(err, ErrInvalidAccessToken) {
+				t.Errorf("ParseAccessToken = %v, want ErrInvalidAccessToken", err)
+			}
+		})
+	}
+}
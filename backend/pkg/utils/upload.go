@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MaxUploadSize is the largest document file accepted by the upload endpoint.
+const MaxUploadSize = 5 << 20
+
+// AllowedUploadTypes maps the accepted MIME types of uploaded documents to their file extension.
+var AllowedUploadTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// ErrUnsupportedFileType is returned when an uploaded file is not an allowed type.
+var ErrUnsupportedFileType = errors.New("unsupported file type")
+
+// DetectUploadType sniffs the MIME type of a file from its first bytes, ignoring the client-supplied type.
+//
+// Parameters:
+// - head ([]byte): Up to the first 512 bytes of the file.
+//
+// Returns:
+// - string: The detected MIME type.
+// - error: ErrUnsupportedFileType if the type is not allowed.
+func DetectUploadType(head []byte) (string, error) {
+	contentType := http.DetectContentType(head)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if _, ok := AllowedUploadTypes[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+	return contentType, nil
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidUsername is returned when a username does not match the allowed format.
+var ErrInvalidUsername = errors.New("invalid username")
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+
+// ValidateUsername checks that a username is 3-32 characters of letters, digits, '.', '_' or '-'.
+func ValidateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("%w: use 3-32 letters, digits, '.', '_' or '-'", ErrInvalidUsername)
+	}
+	return nil
+}
+
+// NormalizeEmail trims and lower-cases an email address so uniqueness checks are case-insensitive.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// UsernameTaken reports whether a user with the given username already exists.
+func UsernameTaken(tx *gorm.DB, username string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error checking username: %w", err)
+	}
+	return count > 0, nil
+}
+
+// EmailTaken reports whether a user with the given email already exists.
+// The email is normalized before comparison.
+func EmailTaken(tx *gorm.DB, email string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.User{}).Where("email = ?", NormalizeEmail(email)).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error checking email: %w", err)
+	}
+	return count > 0, nil
+}
+
+var (
+	// ErrUnknownUser is returned when no user has the given username.
+	ErrUnknownUser = errors.New("invalid username")
+	// ErrWrongPassword is returned when the password does not match.
+	ErrWrongPassword = errors.New("invalid password")
+	// ErrAccountDeactivated is returned when the account has been deactivated.
+	ErrAccountDeactivated = errors.New("account is deactivated")
+)
+
+// AuthenticateUser checks a username and password against the users table.
+// Legacy plain-text or low-cost password hashes are upgraded on success.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - username (string): The username.
+// - password (string): The plain-text password.
+//
+// Returns:
+// - *models.User: The authenticated user.
+// - error: ErrUnknownUser, ErrWrongPassword, ErrAccountDeactivated or a database error.
+func AuthenticateUser(tx *gorm.DB, username, password string) (*models.User, error) {
+	var user models.User
+	if err := tx.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownUser
+		}
+		return nil, err
+	}
+
+	match, needsRehash := VerifyPassword(user.Password, password)
+	if !match {
+		return nil, ErrWrongPassword
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if needsRehash {
+		if hash, err := HashPassword(password); err != nil {
+			log.Printf("failed to rehash password for user %d: %v", user.ID, err)
+		} else if err := tx.Model(&user).Update("password", hash).Error; err != nil {
+			log.Printf("failed to store rehashed password for user %d: %v", user.ID, err)
+		}
+	}
+	return &user, nil
+}
+
+// PasswordResetTTL is the lifetime of a password reset token.
+const PasswordResetTTL = time.Hour
+
+var (
+	// ErrUsernameTaken is returned when a username is already registered.
+	ErrUsernameTaken = errors.New("duplicate username")
+	// ErrEmailTaken is returned when an email is already registered.
+	ErrEmailTaken = errors.New("duplicate email")
+)
+
+// CreateUser validates and creates an active user account.
+//
+// Parameters:
+// - tx (*gorm.DB): The database connection.
+// - username (string): The username.
+// - email (string): The email address; it is normalized before storage.
+// - password (string): The plain-text password.
+// - role (models.Role): The role of the new user.
+//
+// Returns:
+// - *models.User: The created user.
+// - error: ErrInvalidUsername, ErrWeakPassword, ErrUsernameTaken, ErrEmailTaken or a database error.
+// A gorm.ErrDuplicatedKey error means a concurrent signup took the username or email.
+func CreateUser(tx *gorm.DB, username, email, password string, role models.Role) (*models.User, error) {
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := ValidatePasswordPolicy(password); err != nil {
+		return nil, err
+	}
+	if !role.IsValid() {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+
+	if taken, err := UsernameTaken(tx, username); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrUsernameTaken
+	}
+	if taken, err := EmailTaken(tx, email); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrEmailTaken
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeEmail(email)
+	user := models.User{
+		Username: username,
+		Email:    &normalized,
+		Password: hash,
+		Role:     role,
+		IsActive: true,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetPassword validates and hashes a new password, stores it for the user and
+// revokes the user's refresh tokens.
+func SetPassword(tx *gorm.DB, user *models.User, password string) error {
+	if err := ValidatePasswordPolicy(password); err != nil {
+		return err
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(user).Update("password", hash).Error; err != nil {
+		return err
+	}
+	return RevokeRefreshTokens(tx, user.ID)
+}
+
+// SetRole assigns a role to a user and revokes the user's refresh tokens, so sessions
+// issued under the old role end when their access token expires.
+func SetRole(tx *gorm.DB, user *models.User, role models.Role) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("role", role).Error; err != nil {
+			return err
+		}
+		return RevokeRefreshTokens(tx, user.ID)
+	})
+}
+
+// IssuePasswordResetToken creates a single-use password reset token for a user.
+// Only the hash of the token is stored; the token itself is returned once.
+func IssuePasswordResetToken(tx *gorm.DB, userID uint) (*models.PasswordResetResponse, error) {
+	token, hash, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	reset := models.PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(PasswordResetTTL),
+	}
+	if err := tx.Omit("User").Create(&reset).Error; err != nil {
+		return nil, err
+	}
+	return &models.PasswordResetResponse{Token: token, ExpiresAt: reset.ExpiresAt}, nil
+}
+
+// RevokeRefreshTokens revokes all active refresh tokens of a user.
+func RevokeRefreshTokens(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/ChayanDass/beneficiary-manager/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -43,7 +44,7 @@ func CheckApplicationCompleteness(app *models.Application) error {
 		if profile.PhoneNumber == "" {
 			return errors.New("phone number is missing")
 		}
-		if profile.DateOfBirth.IsZero() {
+		if profile.DateOfBirth == nil {
 			return errors.New("date of birth is missing")
 		}
 		if profile.Qualification == "" {
@@ -112,7 +113,7 @@ func CheckApplicationCompleteness(app *models.Application) error {
 		if profile.PhoneNumber != "" && profile.PhoneNumber == "" {
 			return errors.New("phone number is invalid")
 		}
-		if !profile.DateOfBirth.IsZero() && profile.DateOfBirth.IsZero() {
+		if profile.DateOfBirth != nil && profile.DateOfBirth.IsZero() {
 			return errors.New("date of birth is invalid")
 		}
 		if profile.Qualification != "" && profile.Qualification == "" {
@@ -159,7 +160,8 @@ func CheckApplicationCompleteness(app *models.Application) error {
 }
 
 // UpsertStudentAddresses inserts or updates student addresses in the database.
-// It processes only "permanent" and "current" address types, skipping empty addresses.
+// Addresses with an ID replace that address; others are matched by type. Only "permanent" and
+// "current" address types are processed, and empty addresses without an ID are skipped.
 //
 // Parameters:
 // - db (*gorm.DB): The database connection.
@@ -167,9 +169,19 @@ func CheckApplicationCompleteness(app *models.Application) error {
 // - addresses ([]models.AddressInput): The list of address inputs to upsert.
 //
 // Returns:
-// - error: An error if the operation fails, or nil if successful.
+// - error: ValidationErrors if an ID does not belong to the student, or a database error.
 func UpsertStudentAddresses(db *gorm.DB, studentID uint, addresses []models.AddressInput) error {
-	for _, addr := range addresses {
+	for i, addr := range addresses {
+		if addr.ID != 0 {
+			if _, err := UpdateStudentAddress(db, studentID, addr.ID, addr); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ValidationErrors{{Field: fmt.Sprintf("addresses[%d].id", i), Message: "does not exist"}}
+				}
+				return err
+			}
+			continue
+		}
+
 		if addr.Type != "permanent" && addr.Type != "current" {
 			continue // we only process known types
 		}
@@ -181,7 +193,6 @@ func UpsertStudentAddresses(db *gorm.DB, studentID uint, addresses []models.Addr
 
 		var existing models.Address
 		err := db.Where("student_id = ? AND type = ?", studentID, addr.Type).First(&existing).Error
-		fmt.Println("Existing address found:", existing)
 
 		if err != nil && err != gorm.ErrRecordNotFound {
 			return err
@@ -222,7 +233,9 @@ func UpsertStudentAddresses(db *gorm.DB, studentID uint, addresses []models.Addr
 }
 
 // UpsertStudentDocuments inserts or updates student documents in the database.
-// It skips documents with missing name or URL.
+// Documents with an ID update that document; others are matched by name, and those with a
+// missing name or URL are skipped. A signed link to a document's stored file counts as its
+// current URL.
 //
 // Parameters:
 // - db (*gorm.DB): The database connection.
@@ -230,9 +243,19 @@ func UpsertStudentAddresses(db *gorm.DB, studentID uint, addresses []models.Addr
 // - documents ([]models.DocumentInput): The list of document inputs to upsert.
 //
 // Returns:
-// - error: An error if the operation fails, or nil if successful.
+// - error: ValidationErrors if an ID does not belong to the student, or a database error.
 func UpsertStudentDocuments(db *gorm.DB, studentID uint, documents []models.DocumentInput) error {
-	for _, doc := range documents {
+	for i, doc := range documents {
+		if doc.ID != 0 {
+			if _, _, err := UpdateStudentDocument(db, studentID, doc.ID, doc); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ValidationErrors{{Field: fmt.Sprintf("documents[%d].id", i), Message: "does not exist"}}
+				}
+				return err
+			}
+			continue
+		}
+
 		if doc.Name == "" || doc.URL == "" {
 			continue
 		}
@@ -246,18 +269,18 @@ func UpsertStudentDocuments(db *gorm.DB, studentID uint, documents []models.Docu
 
 		if err == gorm.ErrRecordNotFound {
 			newDoc := models.UploadDocument{
-				StudentID: studentID,
-				Name:      doc.Name,
-				URL:       doc.URL,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				StudentID:          studentID,
+				Name:               doc.Name,
+				URL:                doc.URL,
+				VerificationStatus: models.DocumentPending,
+				CreatedAt:          time.Now(),
+				UpdatedAt:          time.Now(),
 			}
 			if err := db.Create(&newDoc).Error; err != nil {
 				return fmt.Errorf("failed to create document: %w", err)
 			}
-		} else {
-			existing.URL = doc.URL
-			existing.UpdatedAt = time.Now()
+		} else if existing.URL != doc.URL && !storage.IsLinkTo(doc.URL, existing.StorageKey) {
+			resetDocumentFile(&existing, doc.URL)
 			if err := db.Save(&existing).Error; err != nil {
 				return fmt.Errorf("failed to update document: %w", err)
 			}
@@ -267,7 +290,7 @@ func UpsertStudentDocuments(db *gorm.DB, studentID uint, documents []models.Docu
 }
 
 // UpsertEducationHistory inserts or updates a student's education history in the database.
-// It skips empty education history entries.
+// Entries with an ID replace that entry; entries without one are added. Empty entries are skipped.
 //
 // Parameters:
 // - db (*gorm.DB): The database connection.
@@ -275,43 +298,24 @@ func UpsertStudentDocuments(db *gorm.DB, studentID uint, documents []models.Docu
 // - history ([]models.EducationHistoryInput): The list of education history inputs to upsert.
 //
 // Returns:
-// - error: An error if the operation fails, or nil if successful.
+// - error: ValidationErrors if an ID does not belong to the student, or a database error.
 func UpsertEducationHistory(db *gorm.DB, studentID uint, history []models.EducationHistoryInput) error {
-	for _, edu := range history {
-		if edu.Degree == "" && edu.University == "" && edu.Course == "" && edu.Grade == "" && edu.YearOfPassing == 0 {
-			continue
-		}
-
-		var existing models.StudentAcademicQualification
-		err := db.Where("student_id = ? AND degree = ? AND university = ? AND course = ?",
-			studentID, edu.Degree, edu.University, edu.Course).First(&existing).Error
-
-		if err != nil && err != gorm.ErrRecordNotFound {
-			return fmt.Errorf("error checking education history: %w", err)
-		}
-
-		if err == gorm.ErrRecordNotFound {
-			newEdu := models.StudentAcademicQualification{
-				StudentID:     studentID,
-				Degree:        edu.Degree,
-				University:    edu.University,
-				YearOfPassing: edu.YearOfPassing,
-				Grade:         edu.Grade,
-				Course:        edu.Course,
-				CreatedAt:     time.Now(),
-				UpdatedAt:     time.Now(),
+	for i, edu := range history {
+		if edu.ID == 0 {
+			if edu.Degree == "" && edu.University == "" && edu.Course == "" && edu.Grade == "" && edu.YearOfPassing == 0 {
+				continue
 			}
-			if err := db.Create(&newEdu).Error; err != nil {
-				return fmt.Errorf("failed to create education record: %w", err)
+			if _, err := CreateEducationEntry(db, studentID, edu); err != nil {
+				return err
 			}
-		} else {
-			existing.YearOfPassing = edu.YearOfPassing
-			existing.Grade = edu.Grade
-			existing.UpdatedAt = time.Now()
+			continue
+		}
 
-			if err := db.Save(&existing).Error; err != nil {
-				return fmt.Errorf("failed to update education record: %w", err)
+		if _, err := UpdateEducationEntry(db, studentID, edu.ID, edu); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ValidationErrors{{Field: fmt.Sprintf("education_history[%d].id", i), Message: "does not exist"}}
 			}
+			return err
 		}
 	}
 	return nil
@@ -401,3 +405,47 @@ func buildURL(basePath string, params url.Values, page int64) string {
 	params.Set("page", strconv.FormatInt(page, 10))
 	return basePath + "?" + params.Encode()
 }
+
+// ApplyApplicationFilters applies the reviewer queue filters to a database query for applications.
+// The query must join student_profiles for the category filter to work.
+//
+// Parameters:
+// - query (*gorm.DB): The initial database query.
+// - filter (models.ApplicationFilter): The filter criteria to apply.
+// - reviewerID (uint): The ID of the calling reviewer, used for assigned_to=me.
+//
+// Returns:
+// - *gorm.DB: The modified query with the applied filters.
+// - error: An error if assigned_to is neither a reviewer ID, "me" nor "unassigned".
+func ApplyApplicationFilters(query *gorm.DB, filter models.ApplicationFilter, reviewerID uint) (*gorm.DB, error) {
+	if filter.SchemeID != nil {
+		query = query.Where("applications.scheme_id = ?", *filter.SchemeID)
+	}
+	if filter.Status != nil {
+		query = query.Where("applications.status = ?", *filter.Status)
+	}
+	if filter.SubmittedAfter != nil {
+		query = query.Where("applications.submitted_at >= ?", *filter.SubmittedAfter)
+	}
+	if filter.SubmittedBefore != nil {
+		query = query.Where("applications.submitted_at <= ?", *filter.SubmittedBefore)
+	}
+	if filter.Category != nil {
+		query = query.Where("student_profiles.category = ?", *filter.Category)
+	}
+	if filter.AssignedTo != nil {
+		switch *filter.AssignedTo {
+		case "me":
+			query = query.Where("applications.assigned_reviewer_id = ?", reviewerID)
+		case "unassigned":
+			query = query.Where("applications.assigned_reviewer_id IS NULL")
+		default:
+			id, err := strconv.ParseUint(*filter.AssignedTo, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("assigned_to must be a reviewer ID, \"me\" or \"unassigned\"")
+			}
+			query = query.Where("applications.assigned_reviewer_id = ?", id)
+		}
+	}
+	return query, nil
+}
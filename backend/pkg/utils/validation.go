@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationErrors lists every invalid field of an input, so clients can show all problems at once.
+type ValidationErrors []models.FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add records an invalid field.
+func (e *ValidationErrors) Add(field, message string) {
+	*e = append(*e, models.FieldError{Field: field, Message: message})
+}
+
+// Err returns the collected errors, or nil if there are none.
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+var (
+	indianPhonePattern = regexp.MustCompile(`^(\+91|0)?[6-9][0-9]{9}$`)
+	e164PhonePattern   = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	aadhaarPattern     = regexp.MustCompile(`^[2-9][0-9]{11}$`)
+	pincodePattern     = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+	postalCodePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 -]{1,9}$`)
+)
+
+// earliestYear bounds dates of birth and years of passing from below.
+const earliestYear = 1900
+
+// inputValidator checks the validate tags of input types. Besides the built-in rules it knows
+// gender, category, qualification, phone, aadhaar, pincode, dob and year.
+var inputValidator = newInputValidator()
+
+func newInputValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	rules := map[string]validator.Func{
+		"gender":        enumRule(models.Genders),
+		"category":      enumRule(models.Categories),
+		"qualification": enumRule(models.AcademicQualifications),
+		"phone": func(fl validator.FieldLevel) bool {
+			phone := strings.NewReplacer(" ", "", "-", "").Replace(fl.Field().String())
+			return indianPhonePattern.MatchString(phone) || e164PhonePattern.MatchString(phone)
+		},
+		"aadhaar": func(fl validator.FieldLevel) bool {
+			return ValidAadhaar(fl.Field().String())
+		},
+		"pincode": func(fl validator.FieldLevel) bool {
+			// Only Indian addresses have to use a six digit PIN code
+			country := fl.Parent().FieldByName("Country").String()
+			if country == "" || strings.EqualFold(country, "India") || strings.EqualFold(country, "IN") {
+				return pincodePattern.MatchString(fl.Field().String())
+			}
+			return postalCodePattern.MatchString(fl.Field().String())
+		},
+		"dob": func(fl validator.FieldLevel) bool {
+			dob, ok := fl.Field().Interface().(time.Time)
+			return ok && dob.Year() >= earliestYear && dob.Before(time.Now())
+		},
+		"year": func(fl validator.FieldLevel) bool {
+			year := fl.Field().Int()
+			return year >= earliestYear && year <= int64(time.Now().Year()+1)
+		},
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+	return v
+}
+
+func enumRule[T ~string](values []T) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return slices.Contains(values, T(fl.Field().String()))
+	}
+}
+
+func enumList[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	return strings.Join(names, ", ")
+}
+
+// fieldMessage describes a failed validate rule for API clients.
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		return "must be at most " + fe.Param() + " characters long"
+	case "gte":
+		return "must not be less than " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "email":
+		return "must be a valid email address"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "gender":
+		return "must be one of " + enumList(models.Genders)
+	case "category":
+		return "must be one of " + enumList(models.Categories)
+	case "qualification":
+		return "must be one of " + enumList(models.AcademicQualifications)
+	case "phone":
+		return "must be a 10 digit Indian mobile number or an international number starting with +"
+	case "aadhaar":
+		return "must be a valid 12 digit Aadhaar number"
+	case "pincode":
+		return "must be a valid postal code; Indian PIN codes have 6 digits"
+	case "dob":
+		return fmt.Sprintf("must be a past date after %d", earliestYear)
+	case "year":
+		return fmt.Sprintf("must be a year between %d and %d", earliestYear, time.Now().Year()+1)
+	default:
+		return "failed the " + fe.Tag() + " check"
+	}
+}
+
+// ValidateStruct checks the validate tags of an input struct.
+//
+// Parameters:
+// - input (interface{}): The input struct or a pointer to it.
+//
+// Returns:
+// - error: ValidationErrors keyed by the JSON path of each invalid field, or nil if the input is valid.
+func ValidateStruct(input interface{}) error {
+	err := inputValidator.Struct(input)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	errs := make(ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		// The namespace starts with the struct name, e.g. StudentProfileInput.addresses[0].pincode
+		_, path, _ := strings.Cut(fe.Namespace(), ".")
+		errs.Add(path, fieldMessage(fe))
+	}
+	return errs
+}
+
+// ValidAadhaar reports whether a number is a well-formed Aadhaar number: twelve digits, not
+// starting with 0 or 1, whose last digit is a valid Verhoeff check digit.
+func ValidAadhaar(number string) bool {
+	if !aadhaarPattern.MatchString(number) {
+		return false
+	}
+	check := 0
+	for i := range number {
+		digit := int(number[len(number)-1-i] - '0')
+		check = verhoeffMultiply[check][verhoeffPermute[i%8][digit]]
+	}
+	return check == 0
+}
+
+var verhoeffMultiply = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffPermute = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// ValidateStudentProfileInput checks a profile input before anything is stored. Field formats
+// come from the validate tags of the input types; rules spanning several entries, such as
+// duplicate documents, are checked here. Entries of documents, addresses and education history
+// that are completely empty are ignored, as the Upsert helpers skip them.
+//
+// Parameters:
+// - input (models.StudentProfileInput): The submitted profile data.
+//
+// Returns:
+// - error: ValidationErrors listing every invalid field, or nil if the input is valid.
+func ValidateStudentProfileInput(input models.StudentProfileInput) error {
+	var errs ValidationErrors
+	if err := ValidateStruct(input); err != nil {
+		if !errors.As(err, &errs) {
+			return err
+		}
+	}
+
+	documents := make(map[string]bool, len(input.Documents))
+	for i, doc := range input.Documents {
+		path := fmt.Sprintf("documents[%d]", i)
+		switch {
+		case doc.ID == 0 && doc.Name == "" && doc.URL == "":
+			continue
+		case doc.Name == "":
+			errs.Add(path+".name", "is required")
+		case doc.URL == "":
+			errs.Add(path+".url", "is required")
+		case documents[doc.Name]:
+			errs.Add(path+".name", "duplicates document "+doc.Name)
+		}
+		documents[doc.Name] = true
+	}
+
+	addresses := make(map[string]bool, len(input.Addresses))
+	for i, addr := range input.Addresses {
+		if addr.ID == 0 && addr.Street == "" && addr.City == "" && addr.State == "" && addr.Pincode == "" && addr.Country == "" {
+			continue
+		}
+		path := fmt.Sprintf("addresses[%d].type", i)
+		switch {
+		case addr.Type == "":
+			errs.Add(path, "is required")
+		case addresses[addr.Type]:
+			errs.Add(path, "duplicates the "+addr.Type+" address")
+		}
+		addresses[addr.Type] = true
+	}
+
+	for i, edu := range input.EducationHistory {
+		if edu.ID == 0 && edu.Degree == "" && edu.University == "" && edu.Course == "" && edu.Grade == "" && edu.YearOfPassing == 0 {
+			continue
+		}
+		if edu.Degree == "" {
+			errs.Add(fmt.Sprintf("education_history[%d].degree", i), "is required")
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChayanDass/beneficiary-manager/pkg/models"
+)
+
+func TestValidAadhaar(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"234123412346", true},
+		{"499118665246", true},
+		{"234123412345", false}, // wrong check digit
+		{"234123412364", false}, // last two digits swapped
+		{"243123412346", false}, // adjacent digits swapped
+		{"499118665247", false},
+		{"123412341234", false}, // starts with 1
+		{"034123412346", false}, // starts with 0
+		{"23412341234", false},  // 11 digits
+		{"2341234123460", false},
+		{"2341 2341 2346", false},
+		{"23412341234a", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidAadhaar(tt.number); got != tt.want {
+			t.Errorf("ValidAadhaar(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestValidateStudentProfileInput(t *testing.T) {
+	dob := time.Date(2004, 5, 17, 0, 0, 0, 0, time.UTC)
+	income := 150000.0
+	valid := models.StudentProfileInput{
+		FullName:      "Asha Rao",
+		DateOfBirth:   &dob,
+		Gender:        string(models.Genders[0]),
+		PhoneNumber:   "+91 98765 43210",
+		Email:         "asha@example.org",
+		AadhaarNumber: "234123412346",
+		Category:      string(models.Categories[0]),
+		Income:        &income,
+		Documents:     []models.DocumentInput{{Name: "aadhar_card", URL: "https://files.example.org/a.pdf"}},
+		Addresses: []models.AddressInput{
+			{Type: "permanent", City: "Pune", Pincode: "411001", Country: "India"},
+			{Type: "current", City: "Berlin", Pincode: "10115", Country: "Germany"},
+		},
+		EducationHistory: []models.EducationHistoryInput{{Degree: "B.Sc", YearOfPassing: 2024}},
+	}
+	if err := ValidateStudentProfileInput(valid); err != nil {
+		t.Fatalf("ValidateStudentProfileInput(valid) = %v", err)
+	}
+
+	future := time.Now().AddDate(1, 0, 0)
+	negative := -1.0
+	tests := []struct {
+		name   string
+		modify func(*models.StudentProfileInput)
+		path   string
+	}{
+		{"invalid Aadhaar", func(in *models.StudentProfileInput) { in.AadhaarNumber = "234123412345" }, "aadhaar_number"},
+		{"invalid phone", func(in *models.StudentProfileInput) { in.PhoneNumber = "12345" }, "phone_number"},
+		{"invalid email", func(in *models.StudentProfileInput) { in.Email = "asha" }, "email"},
+		{"unknown gender", func(in *models.StudentProfileInput) { in.Gender = "unknown" }, "gender"},
+		{"future birth date", func(in *models.StudentProfileInput) { in.DateOfBirth = &future }, "date_of_birth"},
+		{"negative income", func(in *models.StudentProfileInput) { in.Income = &negative }, "income"},
+		{"Indian PIN code", func(in *models.StudentProfileInput) { in.Addresses[1].Country = "India" }, "addresses[1].pincode"},
+		{"unknown address type", func(in *models.StudentProfileInput) { in.Addresses[0].Type = "office" }, "addresses[0].type"},
+		{"duplicate address type", func(in *models.StudentProfileInput) { in.Addresses[1].Type = "permanent" }, "addresses[1].type"},
+		{"document without URL", func(in *models.StudentProfileInput) { in.Documents[0].URL = "" }, "documents[0].url"},
+		{"duplicate document", func(in *models.StudentProfileInput) {
+			in.Documents = append(in.Documents, in.Documents[0])
+		}, "documents[1].name"},
+		{"year of passing", func(in *models.StudentProfileInput) { in.EducationHistory[0].YearOfPassing = 1800 }, "education_history[0].year_of_passing"},
+		{"education without degree", func(in *models.StudentProfileInput) { in.EducationHistory[0].Degree = "" }, "education_history[0].degree"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid
+			input.Documents = append([]models.DocumentInput(nil), valid.Documents...)
+			input.Addresses = append([]models.AddressInput(nil), valid.Addresses...)
+			input.EducationHistory = append([]models.EducationHistoryInput(nil), valid.EducationHistory...)
+			tt.modify(&input)
+
+			var errs ValidationErrors
+			if err := ValidateStudentProfileInput(input); !errors.As(err, &errs) {
+				t.Fatalf("ValidateStudentProfileInput() = %v, want ValidationErrors", err)
+			}
+			if len(errs) != 1 || errs[0].Field != tt.path {
+				t.Errorf("ValidateStudentProfileInput() = %v, want one error for %s", errs, tt.path)
+			}
+		})
+	}
+}
+
+func TestValidateStudentProfileInputReportsEveryField(t *testing.T) {
+	input := models.StudentProfileInput{
+		AadhaarNumber: "234123412345",
+		PhoneNumber:   "12345",
+		Addresses: []models.AddressInput{
+			{Type: "permanent", Pincode: "411001"},
+			{Type: "current", Pincode: "0110"},
+		},
+	}
+	var errs ValidationErrors
+	if err := ValidateStudentProfileInput(input); !errors.As(err, &errs) {
+		t.Fatalf("ValidateStudentProfileInput() = %v, want ValidationErrors", err)
+	}
+	fields := make(map[string]bool, len(errs))
+	for _, fe := range errs {
+		fields[fe.Field] = true
+	}
+	for _, path := range []string{"aadhaar_number", "phone_number", "addresses[1].pincode"} {
+		if !fields[path] {
+			t.Errorf("no error for %s in %v", path, errs)
+		}
+	}
+	if len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+}